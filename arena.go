@@ -0,0 +1,251 @@
+package linkedlist
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Ref is a reference to an arena node, zero is nil reference
+type Ref uint32
+
+// MaxArenaNodes is a maximum capacity of an arena, links are 31 bits wide
+const MaxArenaNodes = 1<<31 - 1
+
+// Layout of arena link word: two flag bits, next and back references
+const (
+	linkFreeze  = 1
+	linkDelete  = 2
+	linkFlags   = linkFreeze | linkDelete
+	linkRefBits = 31
+	linkRefMask = 1<<linkRefBits - 1
+	linkNext    = 2
+	linkBack    = linkNext + linkRefBits
+)
+
+// ErrArenaFull is returned by Alloc once all arena nodes are allocated
+var ErrArenaFull = errors.New("linkedlist: arena is full")
+
+// arenaNode is a node stored in the arena. It has no pointers, so GC never
+// scans arena memory
+type arenaNode struct {
+	link  uint64
+	value uint64
+}
+
+// Arena is a list storage for very large lists. Nodes live in a preallocated
+// slab and refer to each other by 32 bits offsets, state of a node is a single
+// word with next and back references and flags updated by CAS. Arena lists
+// follow exactly the same algorithms and semantic as lists of Node.
+//
+// Arena never reuses nodes, allocated node stays in the slab after deletion
+// until the whole arena is dropped. Every node carries uint64 value, use it as
+// an index into a pointer free storage of payloads if more data is needed
+type Arena struct {
+	nodes []arenaNode
+	used  uint32
+	free  func() error
+}
+
+// NewArena creates arena for the given number of nodes on the Go heap
+func NewArena(capacity int) *Arena {
+	if capacity > MaxArenaNodes {
+		capacity = MaxArenaNodes
+	}
+	return &Arena{nodes: make([]arenaNode, capacity)}
+}
+
+// Close releases arena memory if it has been mapped. Arena must not be used
+// after Close
+func (a *Arena) Close() error {
+	if a.free == nil {
+		return nil
+	}
+
+	free := a.free
+	a.nodes, a.free = nil, nil
+	return free()
+}
+
+// Alloc takes a new node from the arena and sets its value. Node is not linked
+// into any list
+func (a *Arena) Alloc(value uint64) (Ref, error) {
+	i := atomic.AddUint32(&a.used, 1)
+	if int(i) > len(a.nodes) {
+		atomic.AddUint32(&a.used, ^uint32(0))
+		return 0, ErrArenaFull
+	}
+
+	n := &a.nodes[i-1]
+	n.value = value
+	atomic.StoreUint64(&n.link, 0)
+	return Ref(i), nil
+}
+
+// Len returns number of nodes allocated from the arena
+func (a *Arena) Len() int {
+	return int(atomic.LoadUint32(&a.used))
+}
+
+// Value returns value of the node
+func (a *Arena) Value(r Ref) uint64 {
+	return a.nodes[r-1].value
+}
+
+// State returns current state of the node
+func (a *Arena) State(r Ref) (next, back Ref, flags Flags) {
+	next, back, flags = unpackLink(a.load(r))
+	return next, back, flags
+}
+
+// packLink builds link word
+func packLink(next, back Ref, flags uint64) uint64 {
+	return uint64(next)<<linkNext | uint64(back)<<linkBack | flags
+}
+
+// unpackLink splits link word into references and flags
+func unpackLink(link uint64) (next, back Ref, flags Flags) {
+	next = Ref(link >> linkNext & linkRefMask)
+	back = Ref(link >> linkBack & linkRefMask)
+	if link&linkFreeze != 0 {
+		flags |= FREEZE
+	}
+	if link&linkDelete != 0 {
+		flags |= DELETE
+	}
+	return next, back, flags
+}
+
+// linkNextRef returns next reference of the link word
+func linkNextRef(link uint64) Ref {
+	return Ref(link >> linkNext & linkRefMask)
+}
+
+// linkBackRef returns back reference of the link word
+func linkBackRef(link uint64) Ref {
+	return Ref(link >> linkBack & linkRefMask)
+}
+
+// load reads link word of the node
+func (a *Arena) load(r Ref) uint64 {
+	return atomic.LoadUint64(&a.nodes[r-1].link)
+}
+
+// cas updates link word of the node
+func (a *Arena) cas(r Ref, old, new uint64) bool {
+	return atomic.CompareAndSwapUint64(&a.nodes[r-1].link, old, new)
+}
+
+// Next returns node after r or zero if r is the last one. During the travel
+// concurrent removes will be assists to complete
+func (a *Arena) Next(r Ref) Ref {
+	for {
+		link := a.load(r)
+		if link&linkFreeze != 0 {
+			a.completeDelete(r, linkNextRef(link))
+			continue
+		}
+		return linkNextRef(link)
+	}
+}
+
+// Insert adds node after start, see package level Insert
+func (a *Arena) Insert(start, r Ref) (Ref, bool) {
+	left := start
+	for {
+		cur := a.load(left)
+		if cur&linkFlags == 0 {
+			atomic.StoreUint64(&a.nodes[r-1].link, packLink(linkNextRef(cur), 0, 0))
+			if a.cas(left, cur, packLink(r, 0, 0)) {
+				return left, true
+			}
+			continue
+		}
+
+		if cur&linkFreeze != 0 {
+			a.completeDelete(left, linkNextRef(cur))
+			continue
+		}
+
+		// Left node is removed, use its alive predecessor
+		for cur&linkDelete != 0 {
+			left = linkBackRef(cur)
+			cur = a.load(left)
+		}
+	}
+}
+
+// Delete searches node from start and removes it, see package level Delete
+func (a *Arena) Delete(start, del Ref) (Ref, bool, bool) {
+	left, right := start, linkNextRef(a.load(start))
+	for {
+		for right != del {
+			if right == 0 {
+				return 0, false, false
+			}
+			left, right = right, linkNextRef(a.load(right))
+		}
+
+		p, suc, byme := a.WeakDelete(left, right)
+		if suc {
+			return p, suc, byme
+		}
+		left, right = p, linkNextRef(a.load(p))
+	}
+}
+
+// WeakDelete trys to delete right node of the given pair, see package level
+// WeakDelete
+func (a *Arena) WeakDelete(left, right Ref) (Ref, bool, bool) {
+	if a.cas(left, packLink(right, 0, 0), packLink(right, 0, linkFreeze)) {
+		a.completeDelete(left, right)
+		return left, true, true
+	}
+
+	prev := a.load(left)
+	if linkNextRef(prev) != right {
+		return left, false, false
+	}
+
+	if prev&linkFreeze != 0 {
+		a.completeDelete(left, right)
+		return left, true, false
+	}
+
+	for prev&linkDelete != 0 {
+		left = linkBackRef(prev)
+		prev = a.load(left)
+	}
+	if prev&linkFreeze != 0 {
+		a.completeDelete(left, linkNextRef(prev))
+	}
+	return left, false, false
+}
+
+// arenaDeletion is a removal which has to be completed, see deletion
+type arenaDeletion struct {
+	prev, del Ref
+}
+
+// completeDelete marks del as removed and unlinks it from freezed prev, see
+// CompleteDelete. Removals waiting for freezed successors are kept in explicit
+// worklist, so long chains of freezed nodes do not grow stack
+func (a *Arena) completeDelete(prev, del Ref) {
+	var buf [8]arenaDeletion
+	work := append(buf[:0], arenaDeletion{prev: prev, del: del})
+	for len(work) > 0 {
+		cur := work[len(work)-1]
+		expected := a.load(cur.del)
+		if expected&linkDelete == 0 {
+			if expected&linkFreeze != 0 {
+				work = append(work, arenaDeletion{prev: cur.del, del: linkNextRef(expected)})
+				continue
+			}
+			if !a.cas(cur.del, expected, packLink(linkNextRef(expected), cur.prev, linkDelete)) {
+				continue
+			}
+		}
+
+		a.cas(cur.prev, packLink(cur.del, 0, linkFreeze), packLink(linkNextRef(expected), 0, 0))
+		work = work[:len(work)-1]
+	}
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package linkedlist
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// NewMappedArena creates arena for the given number of nodes in anonymous
+// memory mapping outside of the Go heap. Call Close to unmap it
+func NewMappedArena(capacity int) (*Arena, error) {
+	if capacity > MaxArenaNodes {
+		capacity = MaxArenaNodes
+	}
+
+	size := capacity * int(unsafe.Sizeof(arenaNode{}))
+	mem, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Arena{
+		nodes: unsafe.Slice((*arenaNode)(unsafe.Pointer(&mem[0])), capacity),
+		free:  func() error { return syscall.Munmap(mem) },
+	}, nil
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package linkedlist
+
+import "errors"
+
+// NewMappedArena is not supported on this platform, use NewArena
+func NewMappedArena(capacity int) (*Arena, error) {
+	return nil, errors.New("linkedlist: mapped arena is not supported")
+}
@@ -0,0 +1,82 @@
+package linkedlist
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"runtime"
+	"time"
+)
+
+// ErrTooManyAttempts is returned by InsertCtx and DeleteCtx once operation
+// reaches attempts limit of the backoff policy
+var ErrTooManyAttempts = errors.New("linkedlist: too many attempts")
+
+// DefaultBackoff is a policy used by InsertCtx and DeleteCtx if no policy given
+var DefaultBackoff = Backoff{Base: time.Microsecond, Max: time.Millisecond}
+
+// Backoff is a contention management policy. After every failed attempt caller
+// waits random delay between zero and Base doubled for each failure, but not
+// more than Max. Jitter spreads competing threads in time, so they do not fail
+// on the same CAS again
+type Backoff struct {
+	// Base is a maximum delay after the first failed attempt. Zero means no
+	// delay, thread just yields processor
+	Base time.Duration
+
+	// Max limits delay between attempts
+	Max time.Duration
+
+	// MaxAttempts limits number of attempts operation takes, zero means no
+	// limit
+	MaxAttempts int
+}
+
+// Delay returns random delay before the next attempt after given number of
+// failed attempts
+func (b *Backoff) Delay(failures int) time.Duration {
+	if b.Base <= 0 || failures <= 0 {
+		return 0
+	}
+
+	limit := b.Base
+	for i := 1; i < failures && (b.Max <= 0 || limit < b.Max) && limit < math.MaxInt64/2; i++ {
+		limit *= 2
+	}
+	if b.Max > 0 && limit > b.Max {
+		limit = b.Max
+	}
+	return time.Duration(rand.Int63n(int64(limit) + 1))
+}
+
+// wait blocks caller before the next attempt. Function returns error if
+// operation has to be terminated
+func (b *Backoff) wait(ctx context.Context, failures int) error {
+	if b.MaxAttempts > 0 && failures >= b.MaxAttempts {
+		return ErrTooManyAttempts
+	}
+
+	delay := b.Delay(failures)
+	if delay == 0 {
+		runtime.Gosched()
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// policyOf returns given policy or default one
+func policyOf(policy *Backoff) *Backoff {
+	if policy == nil {
+		return &DefaultBackoff
+	}
+	return policy
+}
@@ -0,0 +1,46 @@
+package linkedlist
+
+import "time"
+
+// Clock is a source of time for structures which depend on it. Tests inject a
+// fake clock to control time
+type Clock interface {
+	// Now returns current time
+	Now() time.Time
+	// NewTicker returns ticker which fires every d
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks of a Clock
+type Ticker interface {
+	// C returns channel ticks are delivered to
+	C() <-chan time.Time
+	// Stop turns ticker off
+	Stop()
+}
+
+// SystemClock is a Clock backed by time package
+var SystemClock Clock = systemClock{}
+
+// systemClock implements Clock with time package
+type systemClock struct{}
+
+// Now implements Clock interface
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker implements Clock interface
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// systemTicker implements Ticker with time.Ticker
+type systemTicker struct {
+	*time.Ticker
+}
+
+// C implements Ticker interface
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,524 @@
+// Package durable provides a typed concurrent list persisted with a write-ahead
+// log.
+//
+// Every successful insert and delete appends a record to the log before the
+// call returns. Writers are serialized while they change the list and append
+// the record, so the log order is exactly the linearization order of changes.
+// Waiting for the disk is done outside of that section with group commit: one
+// writer syncs the log on behalf of everybody who appended before it. Readers
+// travel the list without locks as usual.
+//
+// Log is periodically compacted into a snapshot of the list. Open replays the
+// snapshot and the log written after it to restore the list.
+package durable
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/xphoenix/linkedlist"
+)
+
+// ErrClosed is returned by operations on a closed list
+var ErrClosed = errors.New("durable: list is closed")
+
+// Options tunes durable list
+type Options struct {
+	// CompactEvery is a number of log records after which log is compacted
+	// into a snapshot. Zero disables automatic compaction
+	CompactEvery int
+}
+
+// List is a concurrent list of values of type T persisted in a log file. List
+// must be modified only through its own methods, changes made with package
+// linkedlist functions directly are not logged
+type List[T any] struct {
+	path  string
+	codec linkedlist.Codec[*linkedlist.Element[T]]
+	opts  Options
+	list  *linkedlist.List[T]
+
+	// mu serializes writers: change of the list and record append
+	mu      sync.Mutex
+	file    *os.File
+	buf     *bufio.Writer
+	lsn     uint64
+	nextID  uint64
+	ids     map[*linkedlist.Element[T]]uint64
+	elems   map[uint64]*linkedlist.Element[T]
+	records int
+	err     error
+
+	// syncMu serializes log syncs, synced is the last LSN known to be on disk
+	syncMu sync.Mutex
+	synced uint64
+}
+
+// Open restores list from the log at path and its snapshot, creating empty
+// ones if they do not exist. Codec is used to store element values
+func Open[T any](path string, codec linkedlist.Codec[*linkedlist.Element[T]], opts Options) (*List[T], error) {
+	l := &List[T]{
+		path:   path,
+		codec:  codec,
+		opts:   opts,
+		list:   linkedlist.NewList[T](),
+		nextID: 1,
+		ids:    make(map[*linkedlist.Element[T]]uint64),
+		elems:  make(map[uint64]*linkedlist.Element[T]),
+	}
+
+	if err := l.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := l.replay(); err != nil {
+		return nil, err
+	}
+	l.synced = l.lsn
+	return l, nil
+}
+
+// snapshotPath returns path of the log's snapshot
+func (l *List[T]) snapshotPath() string {
+	return l.path + ".snapshot"
+}
+
+// loadSnapshot restores list from snapshot if it exists. Snapshot starts with
+// LSN of the last record it includes and the next element id followed by the
+// list encoded with linkedlist.Encode
+func (l *List[T]) loadSnapshot() error {
+	f, err := os.Open(l.snapshotPath())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var header [16]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return fmt.Errorf("durable: broken snapshot: %w", err)
+	}
+	l.lsn = binary.LittleEndian.Uint64(header[:])
+	l.nextID = binary.LittleEndian.Uint64(header[8:])
+
+	if _, err := linkedlist.Decode(r, l.list.Head(), idCodec[T]{l}); err != nil {
+		return fmt.Errorf("durable: broken snapshot: %w", err)
+	}
+	return nil
+}
+
+// replay applies log records written after the snapshot and opens log for
+// append. Broken record at the end of the log is a write interrupted by crash,
+// log is truncated just before it. Broken record in the middle of the log is
+// reported as ErrCorrupted
+func (l *List[T]) replay() error {
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	// Log might be just created, make its directory entry durable
+	info, err := f.Stat()
+	if err == nil {
+		err = syncDir(l.path)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r, offset := bufio.NewReader(f), int64(0)
+	for {
+		rec, n, err := readRecord(r, info.Size()-offset)
+		if err == io.EOF || err == errTorn {
+			break
+		} else if err == ErrCorrupted {
+			f.Close()
+			return fmt.Errorf("%w at offset %d of %s", ErrCorrupted, offset, l.path)
+		} else if err != nil {
+			f.Close()
+			return err
+		}
+		offset += int64(n)
+
+		// Snapshot might be taken after the record but before log truncation
+		if rec.lsn <= l.lsn {
+			continue
+		}
+		if err := l.apply(rec); err != nil {
+			f.Close()
+			return err
+		}
+		l.lsn = rec.lsn
+		l.records++
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+
+	l.file, l.buf = f, bufio.NewWriter(f)
+	return nil
+}
+
+// apply makes change described by the log record
+func (l *List[T]) apply(rec record) error {
+	switch rec.op {
+	case opInsert:
+		mark := l.list.Head()
+		if rec.after != 0 {
+			e, ok := l.elems[rec.after]
+			if !ok {
+				return fmt.Errorf("durable: record %d inserts after unknown element %d", rec.lsn, rec.after)
+			}
+			mark = e
+		}
+
+		e, err := l.codec.Unmarshal(rec.payload)
+		if err != nil {
+			return err
+		}
+		linkedlist.Insert(mark, e)
+		l.register(rec.id, e)
+		if rec.id >= l.nextID {
+			l.nextID = rec.id + 1
+		}
+
+	case opDelete:
+		e, ok := l.elems[rec.id]
+		if !ok {
+			return fmt.Errorf("durable: record %d deletes unknown element %d", rec.lsn, rec.id)
+		}
+		linkedlist.Delete(l.list.Head(), e)
+		l.unregister(e)
+	}
+	return nil
+}
+
+// register remembers id of the element
+func (l *List[T]) register(id uint64, e *linkedlist.Element[T]) {
+	l.ids[e], l.elems[id] = id, e
+}
+
+// unregister forgets id of the deleted element
+func (l *List[T]) unregister(e *linkedlist.Element[T]) {
+	delete(l.elems, l.ids[e])
+	delete(l.ids, e)
+}
+
+// PushFront inserts value at the front of the list and returns once the change
+// is on disk
+func (l *List[T]) PushFront(v T) (*linkedlist.Element[T], error) {
+	return l.InsertAfter(v, nil)
+}
+
+// InsertAfter inserts value after mark, nil mark means the front of the list.
+// If mark has been deleted value is inserted after its closest alive
+// predecessor. Function returns once the change is on disk
+func (l *List[T]) InsertAfter(v T, mark *linkedlist.Element[T]) (*linkedlist.Element[T], error) {
+	e := linkedlist.NewElement(v)
+	payload, err := l.codec.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	if l.err != nil {
+		l.mu.Unlock()
+		return nil, l.err
+	}
+
+	// Writers are serialized, so deleted mark is already unlinked and its
+	// backlinks lead to the predecessor insert would use anyway. Resolve it
+	// here to log position which replay could find
+	var after uint64
+	var node linkedlist.Node = l.list.Head()
+	for n := mark; n != nil; {
+		if id, ok := l.ids[n]; ok {
+			after, node = id, n
+			break
+		}
+
+		back, _ := linkedlist.LoadState(n).Back.(*linkedlist.Element[T])
+		if back == nil || linkedlist.Node(back) == l.list.Head() {
+			break
+		}
+		n = back
+	}
+
+	id := l.nextID
+	l.nextID++
+	linkedlist.Insert(node, e)
+	l.register(id, e)
+
+	lsn, err := l.append(record{op: opInsert, id: id, after: after, payload: payload})
+	l.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return e, l.sync(lsn)
+}
+
+// Remove deletes element from the list. Function returns true if element has
+// been deleted by this call, in that case it returns once the change is on disk
+func (l *List[T]) Remove(e *linkedlist.Element[T]) (bool, error) {
+	l.mu.Lock()
+	if l.err != nil {
+		l.mu.Unlock()
+		return false, l.err
+	}
+
+	id, ok := l.ids[e]
+	if !ok {
+		l.mu.Unlock()
+		return false, nil
+	}
+
+	linkedlist.Delete(l.list.Head(), e)
+	l.unregister(e)
+
+	lsn, err := l.append(record{op: opDelete, id: id})
+	l.mu.Unlock()
+
+	if err != nil {
+		return false, err
+	}
+	return true, l.sync(lsn)
+}
+
+// append writes record to the log buffer and compacts log if it is time to.
+// Must be called with mu held. Write errors are sticky: list is changed in
+// memory but not in the log, so it refuses further changes
+func (l *List[T]) append(rec record) (uint64, error) {
+	l.lsn++
+	rec.lsn = l.lsn
+
+	if _, err := l.buf.Write(appendRecord(nil, rec)); err != nil {
+		l.err = err
+		return 0, err
+	}
+
+	l.records++
+	if l.opts.CompactEvery > 0 && l.records >= l.opts.CompactEvery {
+		if err := l.compact(); err != nil {
+			l.err = err
+			return 0, err
+		}
+	}
+	return rec.lsn, nil
+}
+
+// sync waits until log is on disk up to the given LSN. The first writer which
+// comes here flushes and syncs log for all records appended so far, writers
+// which come while it is busy wait and most likely find their records synced.
+// Close syncs all records appended before it, so later writers find their
+// records synced as well, or get ErrClosed if that sync has failed
+func (l *List[T]) sync(lsn uint64) error {
+	l.syncMu.Lock()
+	defer l.syncMu.Unlock()
+	if l.synced >= lsn {
+		return nil
+	}
+
+	l.mu.Lock()
+	if l.err == ErrClosed {
+		l.mu.Unlock()
+		return ErrClosed
+	}
+	target, file, err := l.lsn, l.file, l.buf.Flush()
+	if err != nil {
+		l.err = err
+	}
+	l.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	l.synced = target
+	return nil
+}
+
+// Compact writes snapshot of the list and truncates the log
+func (l *List[T]) Compact() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	return l.compact()
+}
+
+// compact writes snapshot of the list and truncates the log. Must be called
+// with mu held, so the list does not change while it is encoded
+func (l *List[T]) compact() error {
+	tmp := l.snapshotPath() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	var header [16]byte
+	binary.LittleEndian.PutUint64(header[:], l.lsn)
+	binary.LittleEndian.PutUint64(header[8:], l.nextID)
+	if _, err := f.Write(header[:]); err != nil {
+		f.Close()
+		return err
+	}
+	if err := linkedlist.Encode(f, l.list.Head(), idCodec[T]{l}); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// Once snapshot is in place log records are not needed anymore. If crash
+	// happens before truncation replay skips records included in snapshot.
+	// Rename must reach the disk before truncation, otherwise crash might
+	// leave the old snapshot with empty log
+	if err := os.Rename(tmp, l.snapshotPath()); err != nil {
+		return err
+	}
+	if err := syncDir(l.snapshotPath()); err != nil {
+		return err
+	}
+	if err := l.buf.Flush(); err != nil {
+		return err
+	}
+	if err := l.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	l.buf.Reset(l.file)
+	l.records = 0
+	return nil
+}
+
+// syncDir syncs directory of the file, so file creation or rename in it is
+// durable
+func syncDir(path string) error {
+	d, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	err = d.Sync()
+	if cerr := d.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Front returns first element of the list or nil if list is empty
+func (l *List[T]) Front() *linkedlist.Element[T] {
+	return l.list.Front()
+}
+
+// Next returns element after e or nil if e is the last one
+func (l *List[T]) Next(e *linkedlist.Element[T]) *linkedlist.Element[T] {
+	return l.list.Next(e)
+}
+
+// Values returns values of all alive elements in order
+func (l *List[T]) Values() []T {
+	return l.list.Values()
+}
+
+// Close syncs the log and closes it
+func (l *List[T]) Close() error {
+	l.syncMu.Lock()
+	defer l.syncMu.Unlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err == ErrClosed {
+		return ErrClosed
+	}
+
+	err := l.buf.Flush()
+	if err == nil {
+		err = l.file.Sync()
+	}
+	if err == nil {
+		l.synced = l.lsn
+	}
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.err = ErrClosed
+	return err
+}
+
+// idCodec stores element id along with its value in the snapshot
+type idCodec[T any] struct {
+	list *List[T]
+}
+
+// Marshal implements linkedlist.Codec interface
+func (c idCodec[T]) Marshal(e *linkedlist.Element[T]) ([]byte, error) {
+	payload, err := c.list.codec.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return append(binary.AppendUvarint(nil, c.list.ids[e]), payload...), nil
+}
+
+// Unmarshal implements linkedlist.Codec interface
+func (c idCodec[T]) Unmarshal(data []byte) (*linkedlist.Element[T], error) {
+	id, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, linkedlist.ErrInvalidFormat
+	}
+
+	e, err := c.list.codec.Unmarshal(data[n:])
+	if err != nil {
+		return nil, err
+	}
+	c.list.register(id, e)
+	return e, nil
+}
+
+// jsonCodec stores element values as JSON
+type jsonCodec[T any] struct{}
+
+// JSONCodec returns codec which stores element values as JSON
+func JSONCodec[T any]() linkedlist.Codec[*linkedlist.Element[T]] {
+	return jsonCodec[T]{}
+}
+
+// Marshal implements linkedlist.Codec interface
+func (jsonCodec[T]) Marshal(e *linkedlist.Element[T]) ([]byte, error) {
+	return json.Marshal(e.Value)
+}
+
+// Unmarshal implements linkedlist.Codec interface
+func (jsonCodec[T]) Unmarshal(data []byte) (*linkedlist.Element[T], error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return linkedlist.NewElement(v), nil
+}
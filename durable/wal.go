@@ -0,0 +1,150 @@
+package durable
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// Log record operations
+const (
+	opInsert byte = 1
+	opDelete byte = 2
+)
+
+// Size of the record header: body length and body checksum
+const recordHeaderSize = 8
+
+// Limit of the record body size replay accepts
+const maxRecordSize = 1 << 30
+
+// ErrCorrupted is returned by Open if the log has a broken record followed by
+// other records. Such log is damaged, not just interrupted by crash
+var ErrCorrupted = errors.New("durable: corrupted record")
+
+// errTorn reports broken record at the end of the log. Replay treats it as a
+// write interrupted by crash
+var errTorn = errors.New("durable: torn record")
+
+// record is a single list change stored in the log
+type record struct {
+	op      byte
+	lsn     uint64
+	id      uint64
+	after   uint64
+	payload []byte
+}
+
+// appendRecord encodes record to the end of buf. Record is stored as body
+// length, body checksum and body itself
+func appendRecord(buf []byte, r record) []byte {
+	start := len(buf)
+	buf = append(buf, make([]byte, recordHeaderSize)...)
+
+	buf = append(buf, r.op)
+	buf = binary.AppendUvarint(buf, r.lsn)
+	buf = binary.AppendUvarint(buf, r.id)
+	if r.op == opInsert {
+		buf = binary.AppendUvarint(buf, r.after)
+		buf = append(buf, r.payload...)
+	}
+
+	body := buf[start+recordHeaderSize:]
+	binary.LittleEndian.PutUint32(buf[start:], uint32(len(body)))
+	binary.LittleEndian.PutUint32(buf[start+4:], crc32.ChecksumIEEE(body))
+	return buf
+}
+
+// readRecord reads next record from the log, remaining is a number of bytes
+// left in the log. Function returns io.EOF at the clean end of the log. Broken
+// record is reported with errTorn if nothing but zeros follows it and with
+// ErrCorrupted otherwise
+func readRecord(r *bufio.Reader, remaining int64) (record, int, error) {
+	if remaining == 0 {
+		return record{}, 0, io.EOF
+	}
+	if remaining < recordHeaderSize {
+		return record{}, 0, errTorn
+	}
+
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return record{}, 0, err
+	}
+
+	// File system might extend file with zeros when crash happens during
+	// append, so zero tail is a torn write as well
+	size := binary.LittleEndian.Uint32(header[:])
+	if size == 0 || size > maxRecordSize {
+		if header == [recordHeaderSize]byte{} && zeroTail(r) {
+			return record{}, 0, errTorn
+		}
+		return record{}, 0, ErrCorrupted
+	}
+	if int64(size) > remaining-recordHeaderSize {
+		return record{}, 0, errTorn
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return record{}, 0, err
+	}
+
+	rec, err := record{}, error(nil)
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(header[4:]) {
+		err = ErrCorrupted
+	} else {
+		rec, err = decodeBody(body)
+	}
+
+	// Broken record is torn only if it is the last one
+	n := recordHeaderSize + int(size)
+	if err != nil && int64(n) == remaining {
+		err = errTorn
+	}
+	return rec, n, err
+}
+
+// zeroTail reports whether the rest of the log is filled with zeros
+func zeroTail(r *bufio.Reader) bool {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err == io.EOF
+		}
+		if b != 0 {
+			return false
+		}
+	}
+}
+
+// decodeBody parses record body
+func decodeBody(body []byte) (record, error) {
+	rec := record{op: body[0]}
+	body = body[1:]
+
+	var n int
+	if rec.lsn, n = binary.Uvarint(body); n <= 0 {
+		return rec, ErrCorrupted
+	}
+	body = body[n:]
+
+	if rec.id, n = binary.Uvarint(body); n <= 0 {
+		return rec, ErrCorrupted
+	}
+	body = body[n:]
+
+	switch rec.op {
+	case opInsert:
+		if rec.after, n = binary.Uvarint(body); n <= 0 {
+			return rec, ErrCorrupted
+		}
+		rec.payload = body[n:]
+	case opDelete:
+	default:
+		return rec, ErrCorrupted
+	}
+	return rec, nil
+}
@@ -0,0 +1,166 @@
+package linkedlist
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// EncodingVersion is a version of the binary format written by Encode
+	EncodingVersion = 1
+
+	// maxRecordSize limits payload size Decode accepts, so corrupted input
+	// does not lead to huge allocations
+	maxRecordSize = 1 << 30
+)
+
+// encodingMagic starts every encoded list
+var encodingMagic = [4]byte{'L', 'L', 'S', 'T'}
+
+var (
+	// ErrInvalidFormat is returned by Decode if input is not an encoded list
+	ErrInvalidFormat = errors.New("linkedlist: invalid format")
+
+	// ErrUnsupportedVersion is returned by Decode if input has been written by
+	// unknown version of the format
+	ErrUnsupportedVersion = errors.New("linkedlist: unsupported format version")
+)
+
+// Codec converts payload of list nodes of type T to bytes and back
+type Codec[T Node] interface {
+	// Marshal encodes node's payload
+	Marshal(node T) ([]byte, error)
+	// Unmarshal creates a new node from the encoded payload
+	Unmarshal(data []byte) (T, error)
+}
+
+// BinaryNode is a node which knows how to encode its payload
+type BinaryNode interface {
+	Node
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// binaryCodec implements Codec for nodes which implement BinaryNode
+type binaryCodec[T BinaryNode] struct {
+	newNode func() T
+}
+
+// BinaryCodec returns codec for nodes implementing encoding.BinaryMarshaler
+// and encoding.BinaryUnmarshaler. Function newNode creates an empty node to
+// unmarshal payload into
+func BinaryCodec[T BinaryNode](newNode func() T) Codec[T] {
+	return binaryCodec[T]{newNode: newNode}
+}
+
+// Marshal implements Codec interface
+func (c binaryCodec[T]) Marshal(node T) ([]byte, error) {
+	return node.MarshalBinary()
+}
+
+// Unmarshal implements Codec interface
+func (c binaryCodec[T]) Unmarshal(data []byte) (T, error) {
+	node := c.newNode()
+	err := node.UnmarshalBinary(data)
+	return node, err
+}
+
+// Encode writes all alive nodes after head to w in traversal order. Format
+// starts with a magic and a version byte and continues with records of node
+// payloads, each prefixed by uvarint of its length plus one. Zero length
+// terminates the list.
+//
+// Encode travels over the list with Next, so it sees a weakly consistent view
+// of the list under concurrent modifications. Nodes which are not of type T
+// result in error. Traversal stops once it gets back to head, so sentinel of a
+// Ring could be encoded as any other head
+func Encode[T Node](w io.Writer, head Node, codec Codec[T]) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(encodingMagic[:]); err != nil {
+		return err
+	}
+	if err := bw.WriteByte(EncodingVersion); err != nil {
+		return err
+	}
+
+	var buf [binary.MaxVarintLen64]byte
+	for cur := Next(head); cur != nil && cur != head; cur = Next(cur) {
+		// Node might be deleted after Next returned it
+		if LoadState(cur).IsRemoved() {
+			continue
+		}
+
+		node, ok := cur.(T)
+		if !ok {
+			return fmt.Errorf("linkedlist: unexpected node type %T", cur)
+		}
+
+		data, err := codec.Marshal(node)
+		if err != nil {
+			return err
+		}
+
+		n := binary.PutUvarint(buf[:], uint64(len(data))+1)
+		if _, err := bw.Write(buf[:n]); err != nil {
+			return err
+		}
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	n := binary.PutUvarint(buf[:], 0)
+	if _, err := bw.Write(buf[:n]); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Decode reads list written by Encode from r and inserts decoded nodes after
+// head keeping their order. Function returns number of nodes inserted
+func Decode[T Node](r io.Reader, head Node, codec Codec[T]) (int, error) {
+	br := bufio.NewReader(r)
+
+	var header [len(encodingMagic) + 1]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return 0, ErrInvalidFormat
+	}
+	if [4]byte(header[:4]) != encodingMagic {
+		return 0, ErrInvalidFormat
+	}
+	if header[4] != EncodingVersion {
+		return 0, ErrUnsupportedVersion
+	}
+
+	count, prev := 0, head
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return count, ErrInvalidFormat
+		}
+		if size == 0 {
+			return count, nil
+		}
+		if size-1 > maxRecordSize {
+			return count, ErrInvalidFormat
+		}
+
+		data := make([]byte, size-1)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return count, ErrInvalidFormat
+		}
+
+		node, err := codec.Unmarshal(data)
+		if err != nil {
+			return count, err
+		}
+
+		Insert(prev, node)
+		prev = node
+		count++
+	}
+}
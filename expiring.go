@@ -0,0 +1,137 @@
+package linkedlist
+
+import (
+	"context"
+	"time"
+)
+
+// ExpiringNode is a node which expires at the deadline
+type ExpiringNode interface {
+	Node
+	Deadline() time.Time
+}
+
+// ExpiringList is a list of nodes with deadlines. Expired nodes are logically
+// deleted by a background sweeper, see Run, and lazily by Next which skips
+// them. Expiry callback is invoked exactly once for every node deleted due to
+// expiry, by the goroutine which won the delete.
+//
+// Nodes deleted with Delete before expiry are not reported to the callback.
+// Node might expire right after Next returned it, callers which need a strict
+// guarantee should check the deadline on their own
+type ExpiringList struct {
+	head     Link
+	clock    Clock
+	onExpire func(ExpiringNode)
+}
+
+// NewExpiringList creates an empty list. Function onExpire is called for every
+// expired node, nil clock means SystemClock
+func NewExpiringList(clock Clock, onExpire func(ExpiringNode)) *ExpiringList {
+	if clock == nil {
+		clock = SystemClock
+	}
+	if onExpire == nil {
+		onExpire = func(ExpiringNode) {}
+	}
+
+	l := &ExpiringList{clock: clock, onExpire: onExpire}
+	l.head.state = &State{}
+	return l
+}
+
+// Head returns sentinel node of the list
+func (l *ExpiringList) Head() Node {
+	return &l.head
+}
+
+// Insert adds node at the front of the list
+func (l *ExpiringList) Insert(node ExpiringNode) {
+	Insert(&l.head, node)
+}
+
+// Delete removes node from the list, see Delete for returned flags
+func (l *ExpiringList) Delete(node ExpiringNode) (bool, bool) {
+	_, removed, byme := Delete(&l.head, node)
+	return removed, byme
+}
+
+// expired reports whether node is expired at the given time
+func expired(node Node, now time.Time) bool {
+	return !now.Before(node.(ExpiringNode).Deadline())
+}
+
+// expire trys to delete expired right node after left and invokes callback if
+// node has been deleted by the current call. Function returns WeakDelete
+// results
+func (l *ExpiringList) expire(left, right Node) (Node, bool, bool) {
+	p, suc, byme := WeakDelete(left, right, &State{})
+	if byme {
+		l.onExpire(right.(ExpiringNode))
+	}
+	return p, suc, byme
+}
+
+// Next returns alive node after the given one or nil. Expired nodes met on the
+// way are deleted
+func (l *ExpiringList) Next(node Node) ExpiringNode {
+	now := l.clock.Now()
+	for cur := node; ; {
+		next := Next(cur)
+		if next == nil {
+			return nil
+		}
+		if !expired(next, now) {
+			return next.(ExpiringNode)
+		}
+
+		// Retry from the same node once expired one is deleted. If list has
+		// changed concurrently just skip expired node, sweeper will delete it
+		if _, suc, _ := l.expire(cur, next); !suc {
+			cur = next
+		}
+	}
+}
+
+// Sweep travels over the whole list and deletes expired nodes. Function
+// returns number of nodes expired by this call
+func (l *ExpiringList) Sweep() int {
+	count, now := 0, l.clock.Now()
+
+	var prev Node = &l.head
+	for cur := Next(prev); cur != nil; cur = Next(prev) {
+		if !expired(cur, now) {
+			prev = cur
+			continue
+		}
+
+		// Node could be already deleted by concurrent thread, expire has
+		// nothing to do then
+		if LoadState(cur).IsRemoved() {
+			prev = cur
+			continue
+		}
+
+		p, _, byme := l.expire(prev, cur)
+		if byme {
+			count++
+		}
+		prev = p
+	}
+	return count
+}
+
+// Run sweeps the list every interval of the clock until context is done
+func (l *ExpiringList) Run(ctx context.Context, interval time.Duration) error {
+	ticker := l.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+			l.Sweep()
+		}
+	}
+}
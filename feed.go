@@ -0,0 +1,220 @@
+package linkedlist
+
+// EventKind identifies type of a list change
+type EventKind int8
+
+const (
+	// EventInsert reports node inserted after Pred
+	EventInsert EventKind = iota
+	// EventDelete reports node logically deleted from the list
+	EventDelete
+	// EventLag reports that subscriber missed Missed events, the last of them
+	// has sequence number Seq
+	EventLag
+)
+
+// String implements Stringer interface
+func (k EventKind) String() string {
+	switch k {
+	case EventInsert:
+		return "INSERT"
+	case EventDelete:
+		return "DELETE"
+	case EventLag:
+		return "LAG"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Event is a single change of the list delivered by Feed
+type Event struct {
+	Seq    uint64
+	Kind   EventKind
+	Pred   Node
+	Node   Node
+	Missed uint64
+}
+
+// Feed is an Observer which turns list changes into an ordered stream of
+// events with sequence numbers. Install feed as a list observer and subscribe
+// to receive events.
+//
+// Feed is a Sequencer: every insert and delete takes a ticket as a part of its
+// linearizing state update, and events are published in ticket order. So an
+// event is published only after events of all operations it depends on, for
+// example insert of a node always goes before its delete. Goroutines which
+// made changes never wait for each other, whichever finds the next event ready
+// publishes it on behalf of the others. A goroutine stalled between ticket and
+// state update delays publishing of later events, once sequenceWindow events are
+// pending operations on the list wait for it. Feed must be installed as the
+// node's observer directly, see Sequencer.
+//
+// Feed keeps a bounded history of the latest events, so subscriber could
+// resume from a known sequence number after reconnect
+type Feed struct {
+	order sequence
+
+	// Fields below are guarded by order's lock
+	seq     uint64
+	history []Event
+	subs    map[*Subscription]struct{}
+}
+
+// Subscription is a stream of feed's events. Subscriber which does not keep up
+// with the feed loses events, once there is a room in the channel it receives
+// EventLag with number of events missed and then continues with the latest ones
+type Subscription struct {
+	// C is a channel events are delivered to
+	C <-chan Event
+
+	ch     chan Event
+	feed   *Feed
+	missed uint64
+	last   uint64
+}
+
+// NewFeed creates feed which keeps given number of latest events for
+// subscribers to resume from
+func NewFeed(history int) *Feed {
+	f := &Feed{
+		history: make([]Event, history),
+		subs:    make(map[*Subscription]struct{}),
+	}
+	f.order.apply = f.publish
+	return f
+}
+
+// OnInsert implements Observer interface. Events reported without ticket are
+// numbered in the order they are reported
+func (f *Feed) OnInsert(pred, node Node) {
+	f.OnInsertAt(f.Ticket(), pred, node)
+}
+
+// OnLogicalDelete implements Observer interface
+func (f *Feed) OnLogicalDelete(node Node) {
+	f.OnLogicalDeleteAt(f.Ticket(), node)
+}
+
+// OnUnlink implements Observer interface. Physical removal is not a list
+// change from subscribers point of view, so it is not published
+func (f *Feed) OnUnlink(pred, node Node) {
+}
+
+// Ticket implements Sequencer interface
+func (f *Feed) Ticket() uint64 {
+	return f.order.ticket()
+}
+
+// Cancel implements Sequencer interface
+func (f *Feed) Cancel(ticket uint64) {
+	f.order.resolve(ticket, true, Event{})
+}
+
+// OnInsertAt implements Sequencer interface
+func (f *Feed) OnInsertAt(ticket uint64, pred, node Node) {
+	f.order.resolve(ticket, false, Event{Kind: EventInsert, Pred: pred, Node: node})
+}
+
+// OnLogicalDeleteAt implements Sequencer interface
+func (f *Feed) OnLogicalDeleteAt(ticket uint64, node Node) {
+	f.order.resolve(ticket, false, Event{Kind: EventDelete, Node: node})
+}
+
+// Seq returns sequence number of the latest published event
+func (f *Feed) Seq() uint64 {
+	f.order.lock()
+	defer f.order.unlock()
+	return f.seq
+}
+
+// Subscribe starts a new subscription with channel of the given capacity.
+// If from is not zero, subscription starts with events from the history with
+// sequence numbers from and above. Events which are not in the history anymore
+// are reported by EventLag
+func (f *Feed) Subscribe(from uint64, buffer int) *Subscription {
+	ch := make(chan Event, buffer)
+	sub := &Subscription{C: ch, ch: ch, feed: f}
+
+	f.order.lock()
+	defer f.order.unlock()
+
+	sub.last = f.seq
+	if from != 0 && from <= f.seq {
+		// Oldest event still in the history
+		oldest := uint64(1)
+		if f.seq > uint64(len(f.history)) {
+			oldest = f.seq - uint64(len(f.history)) + 1
+		}
+
+		sub.last = from - 1
+		if from < oldest {
+			sub.missed, sub.last = oldest-from, oldest-1
+		}
+		for seq := sub.last + 1; seq <= f.seq; seq++ {
+			sub.deliver(f.history[seq%uint64(len(f.history))])
+		}
+		sub.flushLag()
+	}
+
+	f.subs[sub] = struct{}{}
+	return sub
+}
+
+// Close stops subscription and closes its channel
+func (s *Subscription) Close() {
+	s.feed.order.lock()
+	defer s.feed.order.unlock()
+
+	if _, ok := s.feed.subs[s]; ok {
+		delete(s.feed.subs, s)
+		close(s.ch)
+	}
+}
+
+// publish assigns sequence number to the event and delivers it to all
+// subscribers. Must be called with order's lock held
+func (f *Feed) publish(e Event) {
+	f.seq++
+	e.Seq = f.seq
+	if len(f.history) > 0 {
+		f.history[e.Seq%uint64(len(f.history))] = e
+	}
+
+	for sub := range f.subs {
+		sub.deliver(e)
+	}
+}
+
+// deliver sends event to the subscriber without blocking. Must be called with
+// feed's order locked
+func (s *Subscription) deliver(e Event) {
+	if !s.flushLag() {
+		s.missed++
+		s.last = e.Seq
+		return
+	}
+
+	select {
+	case s.ch <- e:
+	default:
+		s.missed++
+	}
+	s.last = e.Seq
+}
+
+// flushLag tries to report missed events if any. Returns false if there is no
+// room in the channel for the report. Must be called with feed's order locked
+func (s *Subscription) flushLag() bool {
+	if s.missed == 0 {
+		return true
+	}
+
+	select {
+	case s.ch <- Event{Seq: s.last, Kind: EventLag, Missed: s.missed}:
+		s.missed = 0
+		return true
+	default:
+		return false
+	}
+}
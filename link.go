@@ -0,0 +1,160 @@
+package linkedlist
+
+import (
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Link is a list hook to embed into user types as the first field, same way as
+// list_head in Linux kernel:
+//
+//	type Item struct {
+//		linkedlist.Link
+//		Value int
+//	}
+//
+// Embedding type implements Node interface through Link, so it works with all
+// package level functions. Besides that NextOf, DeleteOf and Range read states
+// of such nodes directly, without a call of State method or type assertion per
+// hop. Walks are bound by memory latency, so do not expect them to be much
+// faster than Next and Delete on long lists.
+//
+// Zero Link is ready to use with generic functions. Package level functions
+// need initialized Link, call LinkOf once before passing zero node to them
+type Link struct {
+	state *State
+}
+
+// Linked is a pointer to struct type T which embeds Link as the first field
+type Linked[T any] interface {
+	*T
+	Node
+}
+
+// State implements Node interface
+func (l *Link) State() **State {
+	return &l.state
+}
+
+// Load loads state of the link in a threadsafe way
+func (l *Link) Load() *State {
+	return loadState((*unsafe.Pointer)(unsafe.Pointer(&l.state)))
+}
+
+// LinkOf returns Link embedded into node and initializes it if needed.
+// Function panics if node does not embed Link as the first field
+func LinkOf[T any, P Linked[T]](node P) *Link {
+	l := (*Link)(unsafe.Pointer(node))
+	if unsafe.Pointer(node.State()) != unsafe.Pointer(l) {
+		panic(fmt.Sprintf("linkedlist: %T does not embed Link as the first field", node))
+	}
+
+	if l.Load() == nil {
+		atomic.CompareAndSwapPointer((*unsafe.Pointer)(unsafe.Pointer(&l.state)), nil, unsafe.Pointer(&State{}))
+	}
+	return l
+}
+
+// iface is the layout of an interface value with methods
+type iface struct {
+	tab  unsafe.Pointer
+	data unsafe.Pointer
+}
+
+// tabOf returns type word of Node interface holding values of type P
+func tabOf[T any, P Linked[T]](node P) unsafe.Pointer {
+	var n Node = node
+	return (*iface)(unsafe.Pointer(&n)).tab
+}
+
+// linkOf returns Link of node. Type word of the interface is compared with
+// tab of type P, so hop costs a compare instead of a type assertion. On
+// mismatch function falls back to the assertion, which panics if node is not
+// of type P
+func linkOf[T any, P Linked[T]](tab unsafe.Pointer, node Node) *Link {
+	if i := (*iface)(unsafe.Pointer(&node)); i.tab == tab {
+		return (*Link)(i.data)
+	}
+	return (*Link)(unsafe.Pointer(node.(P)))
+}
+
+// InsertOf is Insert for nodes embedding Link
+func InsertOf[T any, P Linked[T]](start, new P) (P, bool) {
+	LinkOf[T, P](start)
+	LinkOf[T, P](new)
+
+	left, inserted := Insert(start, new)
+	return left.(P), inserted
+}
+
+// NextOf is Next for nodes embedding Link. Nodes of the list must have type P,
+// function panics otherwise
+func NextOf[T any, P Linked[T]](start P) P {
+	if l := nextOf[T, P](tabOf[T, P](start), start, LinkOf[T, P](start)); l != nil {
+		return P(unsafe.Pointer(l))
+	}
+	return nil
+}
+
+// nextOf returns Link of node after the given one
+func nextOf[T any, P Linked[T]](tab unsafe.Pointer, node P, l *Link) *Link {
+	for {
+		cur := l.Load()
+
+		// Help freezed nodes
+		if cur.IsFreezed() {
+			help(nil, node, cur.Next)
+			continue
+		}
+
+		if cur.Next == nil {
+			return nil
+		}
+		return linkOf[T, P](tab, cur.Next)
+	}
+}
+
+// Range calls fn for every node after start until fn returns false. Nodes of
+// the list must have type P, function panics otherwise
+func Range[T any, P Linked[T]](start P, fn func(P) bool) {
+	node, l, tab := start, LinkOf[T, P](start), tabOf[T, P](start)
+	for {
+		if l = nextOf[T, P](tab, node, l); l == nil {
+			return
+		}
+		if node = P(unsafe.Pointer(l)); !fn(node) {
+			return
+		}
+	}
+}
+
+// DeleteOf is Delete for nodes embedding Link. Nodes of the list must have
+// type P, function panics otherwise
+func DeleteOf[T any, P Linked[T]](start, delNode P) (P, bool, bool) {
+	update, helped, tab := &State{}, 0, tabOf[T, P](start)
+	var left Node = start
+	right := LinkOf[T, P](start).Load().Next
+	for attempt := 0; ; attempt++ {
+		// Looking for the node to remove, see Delete
+		for right != Node(delNode) {
+			// Not found
+			if right == nil {
+				observeOp(start, attempt, helped)
+				return nil, false, false
+			}
+
+			left, right = right, linkOf[T, P](tab, right).Load().Next
+		}
+
+		// Delete
+		p, suc, byme := weakDelete(nil, left, right, update, &helped)
+		if suc {
+			observeOp(start, attempt, helped)
+			return p.(P), suc, byme
+		}
+
+		// Failed to delete, so start now points to the new predecessor
+		left, right = p, linkOf[T, P](tab, p).Load().Next
+	}
+}
@@ -14,33 +14,73 @@ type Node interface {
 // LoadState loads linkedlist node's state in a threadsafe way
 func LoadState(node Node) *State {
 	p := (*unsafe.Pointer)(unsafe.Pointer(node.State()))
-	return (*State)(atomic.LoadPointer(p))
+	return loadState(p)
 }
 
 // UpdateState performs CAS update of the linkedlist node's state. Method returns
 // true if Compare-And-Swap operation has been completed sucessfully and false
 // otherwise
 func UpdateState(node, eexpectedNext Node, expectedFlags Flags, newState *State) bool {
+	return updateState(nil, node, eexpectedNext, expectedFlags, newState)
+}
+
+// updateState is UpdateState which retires replaced state to the guard's pool
+func updateState(g *Guard, node, eexpectedNext Node, expectedFlags Flags, newState *State) bool {
+	_, updated := updateStateAt(g, node, eexpectedNext, expectedFlags, newState, nil)
+	return updated
+}
+
+// updateStateAt is updateState which takes a ticket of the sequencer, if it is
+// not nil, between the load of the state to replace and CAS. So the ticket is
+// greater than tickets of all updates the replaced state depends on. Ticket
+// of a failed CAS is canceled
+func updateStateAt(g *Guard, node, eexpectedNext Node, expectedFlags Flags, newState *State, seq Sequencer) (uint64, bool) {
 	p := (*unsafe.Pointer)(unsafe.Pointer(node.State()))
 
 	// Load latest value ignoring possible cache in CPU registers/L1 layer. What
 	// is more important this is an atomic load that exlude possibility to see
 	// intermediate write performed by concurrent execution
-	curState := (*State)(atomic.LoadPointer(p))
+	curState := loadState(p)
 
 	// Ensure that latest value is what we expected to have. If it is then CAS it
 	// to finish transaction
-	return (curState.Next == eexpectedNext && curState.Flags == expectedFlags) && atomic.CompareAndSwapPointer(
-		p,
-		unsafe.Pointer(curState),
-		unsafe.Pointer(newState),
-	)
+	var ticket uint64
+	updated := curState.Next == eexpectedNext && curState.Flags == expectedFlags
+	if updated {
+		if seq != nil {
+			ticket = seq.Ticket()
+		}
+
+		updated = atomic.CompareAndSwapPointer(p, unsafe.Pointer(curState), unsafe.Pointer(newState))
+		if !updated && seq != nil {
+			seq.Cancel(ticket)
+		}
+	}
+
+	if stats := statsOf(node); stats != nil {
+		stats.add(casAttempts)
+		if !updated {
+			stats.add(casFailures)
+		}
+	}
+
+	// Replaced state is not reachable from the list anymore, but concurrent
+	// operations might still read it
+	if updated {
+		g.retire(curState)
+	}
+	return ticket, updated
 }
 
 // Next iterates over the give linked list and returns next elemnt in the chain.
 // If given node has no another node linked then nil returns. During the list
 // travel concurrent removes will be assists to complete
 func Next(start Node) Node {
+	return next(nil, start)
+}
+
+// next is Next which uses guard's pool for allocations
+func next(g *Guard, start Node) Node {
 	curNode := start
 	for {
 		cur := LoadState(curNode)
@@ -48,7 +88,7 @@ func Next(start Node) Node {
 
 		// Help freezed nodes
 		if cur.IsFreezed() {
-			CompleteDelete(curNode, cur.Next)
+			help(g, curNode, cur.Next)
 			continue
 		}
 
@@ -0,0 +1,269 @@
+// Package list provides ConcurrentList, a lock-free list with the API of
+// container/list, so code could switch to it by changing imports.
+//
+// All methods are safe for concurrent use, but semantic differs from
+// container/list where concurrency makes it necessary:
+//
+//   - Element.Next returns nil for removed element like container/list does, so
+//     traversal stops at element removed concurrently. Traversal might return
+//     element which is being removed at the moment.
+//   - Element is a handle of an internal list node. MoveToFront links a new node
+//     of the element at the front and unlinks the old one, so element stays
+//     valid. Traversal which is past the front continues from the old position.
+//   - List keeps no link to the previous element, so Back, PushBack and
+//     InsertBefore travel the list from the front and take O(n).
+//   - Len counts element from the start of its insert until it is removed, so
+//     under concurrent modifications it is an upper bound of the number of
+//     elements in the list. Len never goes negative.
+package list
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/xphoenix/linkedlist"
+)
+
+// Element is an element of a ConcurrentList
+type Element struct {
+	// node is the list node currently holding the element
+	node    unsafe.Pointer
+	list    *ConcurrentList
+	removed int32
+
+	// The value stored with this element
+	Value any
+}
+
+// load returns node currently holding the element
+func (e *Element) load() *node {
+	return (*node)(atomic.LoadPointer(&e.node))
+}
+
+// isRemoved reports whether element has been removed from the list
+func (e *Element) isRemoved() bool {
+	return atomic.LoadInt32(&e.removed) != 0
+}
+
+// Next returns the next list element or nil. Next of removed element is nil
+func (e *Element) Next() *Element {
+	if e.list == nil || e.isRemoved() {
+		return nil
+	}
+	return e.list.next(e.load())
+}
+
+// node is a list node holding an element. Element could be held by a few nodes
+// for a moment while it is moved or removed, only the current one is visible
+type node struct {
+	state *linkedlist.State
+	elem  *Element
+}
+
+// State implements linkedlist.Node interface
+func (n *node) State() **linkedlist.State {
+	return &n.state
+}
+
+// visible reports whether node holds alive element
+func (n *node) visible() bool {
+	return n.elem.load() == n && !n.elem.isRemoved()
+}
+
+// ConcurrentList is a lock-free list of values. Zero value is an empty list
+// ready to use
+type ConcurrentList struct {
+	head node
+	len  int64
+}
+
+// List is an alias which allows to use ConcurrentList in place of
+// container/list.List
+type List = ConcurrentList
+
+// New returns an initialized list
+func New() *ConcurrentList {
+	return new(ConcurrentList).Init()
+}
+
+// Init initializes list. Unlike container/list Init does not clear existing
+// list, as it could be used concurrently
+func (l *ConcurrentList) Init() *ConcurrentList {
+	l.init()
+	return l
+}
+
+// init lazily initializes head of zero list
+func (l *ConcurrentList) init() {
+	p := (*unsafe.Pointer)(unsafe.Pointer(&l.head.state))
+	if atomic.LoadPointer(p) == nil {
+		atomic.CompareAndSwapPointer(p, nil, unsafe.Pointer(&linkedlist.State{}))
+	}
+}
+
+// newNode creates node holding element e
+func newNode(e *Element) *node {
+	return &node{state: &linkedlist.State{}, elem: e}
+}
+
+// newElement creates element of the list and its node
+func (l *ConcurrentList) newElement(v any) (*Element, *node) {
+	e := &Element{list: l, Value: v}
+	n := newNode(e)
+	e.node = unsafe.Pointer(n)
+	return e, n
+}
+
+// Len returns the number of elements of list l
+func (l *ConcurrentList) Len() int {
+	return int(atomic.LoadInt64(&l.len))
+}
+
+// Front returns the first element of list l or nil if the list is empty
+func (l *ConcurrentList) Front() *Element {
+	l.init()
+	return l.next(&l.head)
+}
+
+// next returns element after node n or nil, nodes which are not visible are
+// skipped
+func (l *ConcurrentList) next(n linkedlist.Node) *Element {
+	for {
+		next := linkedlist.Next(n)
+		if next == nil {
+			return nil
+		}
+		if n = next; next.(*node).visible() {
+			return next.(*node).elem
+		}
+	}
+}
+
+// Back returns the last element of list l or nil if the list is empty
+func (l *ConcurrentList) Back() *Element {
+	// Element removed during the walk still leads to its successor
+	var last *Element
+	for e := l.Front(); e != nil; e = l.next(e.load()) {
+		last = e
+	}
+	return last
+}
+
+// PushFront inserts a new element e with value v at the front of list l and
+// returns e
+func (l *ConcurrentList) PushFront(v any) *Element {
+	l.init()
+	e, n := l.newElement(v)
+	atomic.AddInt64(&l.len, 1)
+	linkedlist.Insert(&l.head, n)
+	return e
+}
+
+// PushBack inserts a new element e with value v at the back of list l and
+// returns e
+func (l *ConcurrentList) PushBack(v any) *Element {
+	l.init()
+	e, n := l.newElement(v)
+	atomic.AddInt64(&l.len, 1)
+
+	// Link new element to the node without successor, other threads might
+	// append concurrently so start over from the node found last time
+	var last linkedlist.Node = &l.head
+	update := &linkedlist.State{}
+	for {
+		for next := linkedlist.Next(last); next != nil; next = linkedlist.Next(last) {
+			last = next
+		}
+		if linkedlist.WeakAppend(last, update, n) {
+			return e
+		}
+
+		// Last node has been removed, walk back to alive one
+		for state := linkedlist.LoadState(last); state.IsRemoved(); state = linkedlist.LoadState(last) {
+			last = state.Back
+		}
+	}
+}
+
+// InsertBefore inserts a new element e with value v immediately before mark
+// and returns e. If mark is not an element of l, the list is not modified
+func (l *ConcurrentList) InsertBefore(v any, mark *Element) *Element {
+	if mark.list != l {
+		return nil
+	}
+	l.init()
+	e, n := l.newElement(v)
+	atomic.AddInt64(&l.len, 1)
+
+	for !mark.isRemoved() {
+		target := mark.load()
+		if _, inserted := linkedlist.InsertBefore(&l.head, target, n); inserted {
+			return e
+		}
+
+		// Mark might be moved meanwhile, then insert before its new node
+		if mark.load() == target {
+			break
+		}
+	}
+	atomic.AddInt64(&l.len, -1)
+	return nil
+}
+
+// InsertAfter inserts a new element e with value v immediately after mark and
+// returns e. If mark is not an element of l, the list is not modified. If mark
+// is removed or moved concurrently e is inserted after the closest alive
+// predecessor of mark's old position
+func (l *ConcurrentList) InsertAfter(v any, mark *Element) *Element {
+	if mark.list != l || mark.isRemoved() {
+		return nil
+	}
+
+	e, n := l.newElement(v)
+	atomic.AddInt64(&l.len, 1)
+	linkedlist.Insert(mark.load(), n)
+	return e
+}
+
+// Remove removes e from l if e is an element of list l. It returns the element
+// value e.Value
+func (l *ConcurrentList) Remove(e *Element) any {
+	if e.list != l {
+		return e.Value
+	}
+	l.init()
+
+	// Element is removed once it is marked, node is unlinked after that. If
+	// element is moved concurrently either this call finds the new node or
+	// MoveToFront finds the element removed and unlinks the new node itself
+	if atomic.CompareAndSwapInt32(&e.removed, 0, 1) {
+		atomic.AddInt64(&l.len, -1)
+	}
+	linkedlist.Delete(&l.head, e.load())
+	return e.Value
+}
+
+// MoveToFront moves element e to the front of list l. If e is not an element of
+// l or it has been removed, the list is not modified
+func (l *ConcurrentList) MoveToFront(e *Element) {
+	if e.list != l || e.isRemoved() {
+		return
+	}
+	l.init()
+
+	// New node is not visible until element refers it, then the old one is
+	// hidden and could be unlinked. Concurrent move wins otherwise, element
+	// is at the front after it anyway
+	old, n := e.load(), newNode(e)
+	linkedlist.Insert(&l.head, n)
+	if !atomic.CompareAndSwapPointer(&e.node, unsafe.Pointer(old), unsafe.Pointer(n)) {
+		linkedlist.Delete(&l.head, n)
+		return
+	}
+	linkedlist.Delete(&l.head, old)
+
+	// Remove might miss the new node
+	if e.isRemoved() {
+		linkedlist.Delete(&l.head, n)
+	}
+}
@@ -1,5 +1,7 @@
 package linkedlist
 
+import "context"
+
 // Delete searches given node from the specified position in the list and
 // removes it. In case if deletition is sucessful removed node retuns. If
 // node wasn't found in the list function reports nil as return value
@@ -9,27 +11,72 @@ package linkedlist
 // - node has been deleted by the current call
 //
 // Latest flag allows to determinate winner in case if two thread removes same
-// node. Only one of a such threads will get true in latest boolean result
+// node. Only one of a such threads will get true in latest boolean result.
+//
+// Search stops at nil terminator, so function must not be used with Ring: it
+// never returns for a node which is not in the ring
 func Delete(start, delNode Node) (Node, bool, bool) {
-	update := &State{}
+	return deleteNode(nil, start, delNode)
+}
+
+// DeleteCtx is Delete which manages contention with the given policy, nil
+// policy means DefaultBackoff. Function gives up and returns error once context
+// is done or policy's attempts limit reached, node might be still deleted by
+// concurrent thread then
+func DeleteCtx(ctx context.Context, start, delNode Node, policy *Backoff) (Node, bool, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, false, err
+	}
+
+	policy = policyOf(policy)
+	return deleteWith(nil, start, delNode, func(failures int) error {
+		return policy.wait(ctx, failures)
+	})
+}
+
+// deleteNode is Delete which uses guard's pool for allocations
+func deleteNode(g *Guard, start, delNode Node) (Node, bool, bool) {
+	p, suc, byme, _ := deleteWith(g, start, delNode, nil)
+	return p, suc, byme
+}
+
+// deleteWith runs delete attempts until node removed or not found. Function
+// wait is called after every failed attempt, if it returns error delete
+// terminates
+func deleteWith(g *Guard, start, delNode Node, wait func(failures int) error) (Node, bool, bool, error) {
+	update, helped := g.newState(), 0
 	left, right := start, LoadState(start).Next
-	for {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && wait != nil {
+			if err := wait(attempt); err != nil {
+				observeOp(start, attempt, helped)
+				g.recycle(update)
+				return nil, false, false, err
+			}
+		}
+
 		// Looking for the node to remove. Do not pay attention
 		// onto possible node states during the scan, all combinations
 		// will be handled by WeakDelete
 		for right != delNode {
-			left, right = right, LoadState(right).Next
-
 			// Not found
 			if right == nil {
-				return nil, false, false
+				observeOp(start, attempt, helped)
+				g.recycle(update)
+				return nil, false, false, nil
 			}
+
+			left, right = right, LoadState(right).Next
 		}
 
 		// Delete
-		p, suc, byme := WeakDelete(left, right, update)
+		p, suc, byme := weakDelete(g, left, right, update, &helped)
 		if suc {
-			return p, suc, byme
+			observeOp(start, attempt, helped)
+			if !byme {
+				g.recycle(update)
+			}
+			return p, suc, byme, nil
 		}
 
 		// Failed to delete, so start now points to the new predecessor
@@ -52,6 +99,12 @@ func Delete(start, delNode Node) (Node, bool, bool) {
 // - is right node has been deleted
 // - is right node has been deleted by the current thread
 func WeakDelete(left, right Node, update *State) (Node, bool, bool) {
+	return weakDelete(nil, left, right, update, nil)
+}
+
+// weakDelete is WeakDelete which uses guard's pool for allocations and counts
+// removals completed on behalf of other threads in helped if it is not nil
+func weakDelete(g *Guard, left, right Node, update *State, helped *int) (Node, bool, bool) {
 	update.Next = right
 	update.Back = nil
 	update.Flags = FREEZE
@@ -63,9 +116,12 @@ func WeakDelete(left, right Node, update *State) (Node, bool, bool) {
 	// Once node marked as freezed it successor will be removed during any list
 	// operation (delete, insert or next). So report node deleted and deleted by
 	// the current thread
-	if UpdateState(left, right, NONE, update) {
+	o := observerOf(left)
+	if ticket, ok := updateStateAt(g, left, right, NONE, update, sequencerOf(o)); ok {
 		// fmt.Printf("Freezed: %s -> %s\n", prevNode, delNode)
-		CompleteDelete(left, right)
+		notifyLogicalDelete(o, ticket, right)
+		addHelps(helped, completeDelete(g, left, right))
+		count(left, deletes)
 		return left, true, true
 	}
 
@@ -82,16 +138,24 @@ func WeakDelete(left, right Node, update *State) (Node, bool, bool) {
 	// freezed node, so just help some other thread to complete
 	// removal
 	if prev.IsFreezed() {
-		CompleteDelete(left, right)
+		addHelps(helped, help(g, left, right))
 		return left, true, false
 	}
 
 	// 3. Concurrent thread might already delete node, in that
 	// case we need to step back and find a new delNode predessor
 	for prev.IsRemoved() {
+		count(left, backlinkHops)
 		left, prev = prev.Back, LoadState(prev.Back)
 	}
 
+	// Predecessor found by backlinks might still be freezed on the removed node
+	// if deleting thread stalls. Help it to complete, otherwise caller rescans
+	// the list from the same position and finds the same removed node again
+	if prev.IsFreezed() {
+		addHelps(helped, help(g, left, prev.Next))
+	}
+
 	// 3. It could be that delNode is not a successor of prevNode because
 	// has been removed or because prevNode has been deleted and we walked
 	// too far by backlinks. In any way we need to search for the key again
@@ -103,8 +167,51 @@ func WeakDelete(left, right Node, update *State) (Node, bool, bool) {
 // Prev node is freezed and it means that del node should be removed in a few
 // steps: setup backlink, mark as removed and delete it phisically
 func CompleteDelete(prev, del Node) {
+	completeDelete(nil, prev, del)
+}
+
+// deletion is a removal which has to be completed: del node has to be marked
+// and unlinked from freezed prev node
+type deletion struct {
+	prev, del Node
+}
+
+// completeDelete is CompleteDelete which uses guard's pool for allocations.
+//
+// If del node is freezed itself then its successor should be removed before
+// del node. Chain of freezed nodes could be arbitrary long, so removals which
+// wait for successors are kept in explicit worklist instead of recursion and
+// stack stays bounded. Function returns number of removals of freezed
+// successors it has completed
+func completeDelete(g *Guard, prev, del Node) int {
+	var buf [8]deletion
+	helped, work := 0, append(buf[:0], deletion{prev: prev, del: del})
+	for len(work) > 0 {
+		cur := work[len(work)-1]
+		if expected := LoadState(cur.del); !expected.IsRemoved() && expected.IsFreezed() {
+			count(cur.del, helps)
+			helped++
+			work = append(work, deletion{prev: cur.del, del: expected.Next})
+			continue
+		}
+
+		// Successor of del node might be freezed once again, then its removal
+		// goes first
+		if !markAndUnlink(g, cur.prev, cur.del) {
+			continue
+		}
+		work = work[:len(work)-1]
+	}
+	return helped
+}
+
+// markAndUnlink marks del node as removed and unlinks it from freezed prev
+// node. Function returns false if del node has been freezed by concurrent
+// thread, so its successor should be removed first
+func markAndUnlink(g *Guard, prev, del Node) bool {
 	// First of all mark DEL node as removed
-	expected, new := LoadState(del), &State{Next: nil, Back: prev, Flags: DELETE}
+	marked, expected, new := false, LoadState(del), g.newState()
+	new.Back, new.Flags = prev, DELETE
 	for !expected.IsRemoved() {
 		// Calculate new desired state and try to setup
 		new.Next = expected.Next
@@ -112,9 +219,13 @@ func CompleteDelete(prev, del Node) {
 		// If del node freezed then it successor should be removed before
 		// remove del node itself
 		if expected.IsFreezed() {
-			CompleteDelete(del, expected.Next)
-		} else if UpdateState(del, expected.Next, NONE, new) {
+			g.recycle(new)
+			return false
+		}
+
+		if updateState(g, del, expected.Next, NONE, new) {
 			// fmt.Printf("Marked: %s -> %s\n", prev, del)
+			marked = true
 			break
 		}
 
@@ -124,6 +235,26 @@ func CompleteDelete(prev, del Node) {
 		expected = LoadState(del)
 	}
 
+	if !marked {
+		g.recycle(new)
+	}
+
 	// Unlink node, consider two options:
-	UpdateState(prev, del, FREEZE, &State{Next: expected.Next, Back: nil, Flags: NONE})
+	unlink := g.newState()
+	unlink.Next = expected.Next
+	if updateState(g, prev, del, FREEZE, unlink) {
+		if o := observerOf(prev); o != nil {
+			o.OnUnlink(prev, del)
+		}
+	} else {
+		g.recycle(unlink)
+	}
+	return true
+}
+
+// help completes removal of a node started by another thread and accounts it
+// in statistics. Function returns number of removals completed
+func help(g *Guard, prev, del Node) int {
+	count(prev, helps)
+	return 1 + completeDelete(g, prev, del)
 }
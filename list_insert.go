@@ -1,5 +1,7 @@
 package linkedlist
 
+import "context"
+
 // Weak operations implemnt general logic of linkedlist but support two major invariants:
 // - no intermediate state allocations
 // - no structural changes recovery
@@ -13,14 +15,116 @@ package linkedlist
 
 // Insert adds given node just after another
 func Insert(start, new Node) (Node, bool) {
-	curNode, update, inserted := start, &State{}, false
-	for !inserted {
-		cur := LoadState(curNode)
-		curNode, inserted = WeakInsert(curNode, cur.Next, update, new)
+	return insert(nil, start, new)
+}
+
+// InsertCtx is Insert which manages contention with the given policy, nil
+// policy means DefaultBackoff. Function gives up and returns error once context
+// is done or policy's attempts limit reached, node is not inserted then
+func InsertCtx(ctx context.Context, start, new Node, policy *Backoff) (Node, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+
+	policy = policyOf(policy)
+	return insertWith(nil, start, new, func(failures int) error {
+		return policy.wait(ctx, failures)
+	})
+}
+
+// insert is Insert which uses guard's pool for allocations
+func insert(g *Guard, start, new Node) (Node, bool) {
+	curNode, _ := insertWith(g, start, new, nil)
 	return curNode, true
 }
 
+// insertWith runs insert attempts until success. Function wait is called
+// after every failed attempt, if it returns error insert terminates
+func insertWith(g *Guard, start, new Node, wait func(failures int) error) (Node, error) {
+	curNode, update, inserted, helped := start, g.newState(), false, 0
+	for attempt := 0; !inserted; attempt++ {
+		if attempt > 0 && wait != nil {
+			if err := wait(attempt); err != nil {
+				observeOp(start, attempt, helped)
+				g.recycle(update)
+				return nil, err
+			}
+		}
+
+		cur := LoadState(curNode)
+		curNode, inserted = weakInsert(g, curNode, cur.Next, update, new, &helped)
+		if inserted {
+			observeOp(start, attempt, helped)
+		}
+	}
+	return curNode, nil
+}
+
+// InsertBefore adds given node just before target. Predecessor of target is
+// searched from head, if it gets deleted concurrently search continues from
+// its alive predecessor found by backlinks. Node is linked only while target
+// is alive and directly follows the predecessor.
+//
+// Function returns predecessor new node has been inserted after and true, or
+// nil and false if target is not found in the list or has been removed.
+// Function must not be used with Ring, see Delete
+func InsertBefore(head, target, new Node) (Node, bool) {
+	return insertBefore(nil, head, target, new)
+}
+
+// insertBefore is InsertBefore which uses guard's pool for allocations
+func insertBefore(g *Guard, head, target, new Node) (Node, bool) {
+	update, helped := g.newState(), 0
+	update.Next = new
+
+	left, right := head, LoadState(head).Next
+	for attempt := 0; ; attempt++ {
+		// Looking for predecessor of target
+		for right != target {
+			// Not found
+			if right == nil {
+				observeOp(head, attempt, helped)
+				g.recycle(update)
+				return nil, false
+			}
+
+			left, right = right, LoadState(right).Next
+		}
+
+		if LoadState(target).IsRemoved() {
+			observeOp(head, attempt, helped)
+			g.recycle(update)
+			return nil, false
+		}
+
+		// Predecessor in NONE state guaranties that target is not being
+		// deleted
+		(*new.State()).Next = target
+		o := observerOf(left)
+		if ticket, ok := updateStateAt(g, left, target, NONE, update, sequencerOf(o)); ok {
+			observeOp(head, attempt, helped)
+			count(left, inserts)
+			notifyInsert(o, ticket, left, new)
+			return left, true
+		}
+
+		// Predecessor is freezed, complete removal of its successor. If that
+		// is target then it will be found removed on the next attempt
+		cur := LoadState(left)
+		if cur.IsFreezed() {
+			helped += help(g, left, cur.Next)
+			cur = LoadState(left)
+		}
+
+		// Predecessor is removed, continue from its alive predecessor
+		for cur.IsRemoved() {
+			count(left, backlinkHops)
+			left, cur = cur.Back, LoadState(cur.Back)
+		}
+		right = cur.Next
+	}
+}
+
 // WeakInsert trys to insert node in between of two given nodes. It completes concurrent
 // deletition of the right node if it has been detected and trys to proced if possible.
 //
@@ -33,17 +137,25 @@ func Insert(start, new Node) (Node, bool) {
 // flag indicates was insertion complete or no. In case if left node detected to be
 // removed first alive predecessor of it will be returned
 func WeakInsert(left, right Node, update *State, new Node) (Node, bool) {
+	return weakInsert(nil, left, right, update, new, nil)
+}
+
+// weakInsert is WeakInsert which uses guard's pool for allocations and counts
+// removals completed on behalf of other threads in helped if it is not nil
+func weakInsert(g *Guard, left, right Node, update *State, new Node, helped *int) (Node, bool) {
 	update.Flags = NONE
 	update.Back = nil
 	update.Next = new
 
-	cur, newState := LoadState(left), *new.State()
+	cur, newState, o := LoadState(left), *new.State(), observerOf(left)
 	for {
 		// Prepare new node and insert it
 		newState.Next = cur.Next
-		if UpdateState(left, cur.Next, NONE, update) {
+		if ticket, ok := updateStateAt(g, left, cur.Next, NONE, update, sequencerOf(o)); ok {
 			// DEBUG:
 			// fmt.Printf("Inserted: %s -> %s\n", curNode, cur.Next)
+			count(left, inserts)
+			notifyInsert(o, ticket, left, new)
 			return left, true
 		}
 
@@ -56,14 +168,39 @@ func WeakInsert(left, right Node, update *State, new Node) (Node, bool) {
 		}
 
 		if cur.IsFreezed() {
-			CompleteDelete(left, right)
+			addHelps(helped, help(g, left, right))
 			continue
 		}
 
 		// start node got new child
 		for cur.IsRemoved() {
+			count(left, backlinkHops)
 			left, cur = cur.Back, LoadState(cur.Back)
 		}
 		return left, false
 	}
 }
+
+// WeakAppend trys to link node after last, which must be the tail of the list:
+// operation succeeds only if last is alive and has no successor. Like other
+// weak operations it does not recover from concurrent structural changes and
+// returns false, then caller should find the new tail and try again
+func WeakAppend(last Node, update *State, new Node) bool {
+	return weakAppend(nil, last, update, new)
+}
+
+// weakAppend is WeakAppend which uses guard's pool for allocations
+func weakAppend(g *Guard, last Node, update *State, new Node) bool {
+	update.Flags = NONE
+	update.Back = nil
+	update.Next = new
+	(*new.State()).Next = nil
+
+	o := observerOf(last)
+	ticket, ok := updateStateAt(g, last, nil, NONE, update, sequencerOf(o))
+	if ok {
+		count(last, inserts)
+		notifyInsert(o, ticket, last, new)
+	}
+	return ok
+}
@@ -0,0 +1,152 @@
+package linkedlist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Element is a node of the typed list which carries value of type T
+type Element[T any] struct {
+	state *State
+	Value T
+}
+
+// NewElement creates a new element which is not linked into any list yet
+func NewElement[T any](v T) *Element[T] {
+	return &Element[T]{state: &State{}, Value: v}
+}
+
+// State implements Node interface
+func (e *Element[T]) State() **State {
+	return &e.state
+}
+
+// List is a concurrent list of values of type T. List holds a sentinel head
+// element, so zero value is an empty list ready to use.
+//
+// List encodes to JSON and gob as an array of values in order. Encoding skips
+// logically deleted elements and works with weakly consistent view of the list,
+// so it is safe to encode a list which is being modified
+type List[T any] struct {
+	head Element[T]
+}
+
+// NewList creates a new empty list
+func NewList[T any]() *List[T] {
+	return &List[T]{}
+}
+
+// Head returns sentinel node of the list which could be used with package
+// level functions
+func (l *List[T]) Head() Node {
+	p := (*unsafe.Pointer)(unsafe.Pointer(&l.head.state))
+	if atomic.LoadPointer(p) == nil {
+		atomic.CompareAndSwapPointer(p, nil, unsafe.Pointer(&State{}))
+	}
+	return &l.head
+}
+
+// PushFront inserts a new element with value v at the front of the list
+func (l *List[T]) PushFront(v T) *Element[T] {
+	e := NewElement(v)
+	Insert(l.Head(), e)
+	return e
+}
+
+// InsertAfter inserts a new element with value v just after mark. If mark has
+// been deleted value is inserted after the closest alive predecessor of mark
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	e := NewElement(v)
+	Insert(mark, e)
+	return e
+}
+
+// Remove deletes element from the list. Function returns true if element has
+// been deleted by this call
+func (l *List[T]) Remove(e *Element[T]) bool {
+	_, _, byme := Delete(l.Head(), e)
+	return byme
+}
+
+// Front returns first element of the list or nil if list is empty
+func (l *List[T]) Front() *Element[T] {
+	return l.Next(&l.head)
+}
+
+// Next returns element after e or nil if e is the last one
+func (l *List[T]) Next(e *Element[T]) *Element[T] {
+	if e == &l.head {
+		l.Head()
+	}
+
+	next := Next(e)
+	if next == nil {
+		return nil
+	}
+	return next.(*Element[T])
+}
+
+// Values returns values of all alive elements in order
+func (l *List[T]) Values() []T {
+	var values []T
+	for e := l.Front(); e != nil; e = l.Next(e) {
+		// Element might be deleted after Next returned it
+		if !LoadState(e).IsRemoved() {
+			values = append(values, e.Value)
+		}
+	}
+	return values
+}
+
+// pushValues inserts values at the front of the list keeping their order
+func (l *List[T]) pushValues(values []T) {
+	prev := l.Head()
+	for _, v := range values {
+		e := NewElement(v)
+		Insert(prev, e)
+		prev = e
+	}
+}
+
+// MarshalJSON implements json.Marshaler interface
+func (l *List[T]) MarshalJSON() ([]byte, error) {
+	values := l.Values()
+	if values == nil {
+		values = []T{}
+	}
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. Decoded values are
+// inserted at the front of the list in order
+func (l *List[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	l.pushValues(values)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder interface
+func (l *List[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(l.Values()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder interface. Decoded values are inserted
+// at the front of the list in order
+func (l *List[T]) GobDecode(data []byte) error {
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return err
+	}
+	l.pushValues(values)
+	return nil
+}
@@ -0,0 +1,169 @@
+package linkedlist
+
+import (
+	"sort"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Status of a multi-word CAS operation
+const (
+	mcasUndecided int32 = iota
+	mcasSucceeded
+	mcasFailed
+)
+
+// Update is a single word of a multi-word CAS: state of Node is replaced by New
+// if it is Old. Update which New is Old only compares: node must have Old state
+// for operation to succeed, but its slot is not changed
+type Update struct {
+	Node Node
+	Old  *State
+	New  *State
+}
+
+// mcasDescriptor describes multi-word CAS in progress. While operation is
+// undecided its placeholder state is installed into every slot it updates
+type mcasDescriptor struct {
+	status      int32
+	updates     []Update
+	placeholder *State
+}
+
+// MCAS atomically replaces states of several nodes, either all nodes get new
+// states or none of them. Operation succeeds only if every node has Old state
+// at the moment, so nodes of different lists could be changed together, for
+// example to delete a node from one list and insert it into another one.
+//
+// Node moved that way links into the target list right away, so operation of
+// the source list which has reached the node continues in the target list:
+// concurrent Delete of a node following it in the source list walks into the
+// target one and misses. Move only nodes no such operation could reach, for
+// example nodes of a list owned by the caller. Otherwise delete node from the
+// source list first and insert a new one into the target list.
+//
+// MCAS follows descriptor based algorithm of Harris, Fraser and Pratt: a
+// placeholder which refers the operation is installed into every state slot,
+// then operation is decided and placeholders are replaced by new or old states.
+// Every reader which meets placeholder in LoadState or UpdateState helps the
+// operation to complete first, so placeholders are never visible outside.
+// Compared slots get no placeholder, they are checked once all other slots are
+// acquired, so readers of such slots never wait for the operation.
+//
+// Algorithm relies on uniqueness of State pointers: a state replaced in a slot
+// never gets back into it. So MCAS must not be used with Pool which reuses
+// states. Observers and statistics are not notified about MCAS updates
+func MCAS(updates ...Update) bool {
+	d := &mcasDescriptor{updates: make([]Update, len(updates))}
+	d.placeholder = &State{desc: d}
+	copy(d.updates, updates)
+
+	// Slots are acquired in the same order by every operation, so concurrent
+	// operations do not help each other in a cycle
+	sort.Slice(d.updates, func(i, j int) bool {
+		return uintptr(unsafe.Pointer(d.updates[i].Node.State())) < uintptr(unsafe.Pointer(d.updates[j].Node.State()))
+	})
+	return d.help()
+}
+
+// help completes operation and reports whether it has succeeded
+func (d *mcasDescriptor) help() bool {
+	if atomic.LoadInt32(&d.status) == mcasUndecided {
+		status := mcasSucceeded
+
+	install:
+		for _, u := range d.updates {
+			if u.New == u.Old {
+				continue
+			}
+
+			p := (*unsafe.Pointer)(unsafe.Pointer(u.Node.State()))
+			for {
+				cur := (*State)(atomic.LoadPointer(p))
+				if cur == d.placeholder {
+					break
+				}
+
+				// Slot is acquired by other operation, complete it first
+				if cur != nil && cur.desc != nil {
+					cur.desc.help()
+					continue
+				}
+
+				if cur != u.Old {
+					status = mcasFailed
+					break install
+				}
+
+				// Operation might be decided by other thread meanwhile, do not
+				// acquire slots once it happens
+				if atomic.LoadInt32(&d.status) != mcasUndecided {
+					break install
+				}
+				if atomic.CompareAndSwapPointer(p, unsafe.Pointer(cur), unsafe.Pointer(d.placeholder)) {
+					break
+				}
+			}
+		}
+
+		// States are unique, so compared slot which has Old state now has kept
+		// it since Old has been read. Together with acquired slots it gives a
+		// moment when every node had Old state
+		if status == mcasSucceeded {
+			for _, u := range d.updates {
+				if u.New == u.Old && !d.compare(u) {
+					status = mcasFailed
+					break
+				}
+			}
+		}
+		atomic.CompareAndSwapInt32(&d.status, mcasUndecided, status)
+	}
+
+	// Release slots
+	succeeded := atomic.LoadInt32(&d.status) == mcasSucceeded
+	for _, u := range d.updates {
+		if u.New == u.Old {
+			continue
+		}
+
+		p := (*unsafe.Pointer)(unsafe.Pointer(u.Node.State()))
+		state := u.Old
+		if succeeded {
+			state = u.New
+		}
+		atomic.CompareAndSwapPointer(p, unsafe.Pointer(d.placeholder), unsafe.Pointer(state))
+	}
+	return succeeded
+}
+
+// compare reports whether compared slot has Old state. Slot acquired by other
+// undecided operation is treated as changed: helping it might wait for this
+// operation in a cycle
+func (d *mcasDescriptor) compare(u Update) bool {
+	p := (*unsafe.Pointer)(unsafe.Pointer(u.Node.State()))
+	for {
+		cur := (*State)(atomic.LoadPointer(p))
+		if cur == nil || cur.desc == nil {
+			return cur == u.Old
+		}
+		if atomic.LoadInt32(&cur.desc.status) == mcasUndecided {
+			return false
+		}
+
+		// Decided operation only releases its slots
+		cur.desc.help()
+	}
+}
+
+// loadState loads state from the slot completing multi-word CAS operation if
+// it is in progress on the slot
+func loadState(p *unsafe.Pointer) *State {
+	for {
+		state := (*State)(atomic.LoadPointer(p))
+		if state == nil || state.desc == nil {
+			return state
+		}
+		state.desc.help()
+	}
+}
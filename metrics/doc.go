@@ -0,0 +1,15 @@
+// Package metrics exports linkedlist statistics to monitoring systems.
+//
+// Both exporters read a linkedlist.Stats collector of a single list on scrape
+// or collection, so list operations pay nothing but the Stats counters. Every
+// metric is labeled with the list name given on registration, which allows to
+// find the list that became a contention hotspot:
+//
+//	stats := linkedlist.NewStats()
+//	// ... make list nodes report into stats via linkedlist.StatsNode
+//	prometheus.MustRegister(metrics.NewCollector("sessions", stats))
+//
+// Length estimate is a difference between inserts and deletes counted since
+// the collector has been installed, so it is exact only if the collector was
+// installed on an empty list.
+package metrics
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/xphoenix/linkedlist"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterMeter creates asynchronous OpenTelemetry instruments for the list
+// with the given name and registers a callback which reports stats snapshot
+// on every collection. Use returned registration to stop reporting.
+//
+// OpenTelemetry has no asynchronous histograms, so retries and helps
+// distributions are reported as a counter per bucket with the bucket's upper
+// bound in the "le" attribute. Like Prometheus buckets the counters are
+// cumulative: bucket counts calls with value up to the bound, the last bucket
+// has "+Inf" bound and counts all calls
+func RegisterMeter(meter metric.Meter, list string, stats *linkedlist.Stats) (metric.Registration, error) {
+	var errs []error
+	counter := func(name, help string) metric.Int64ObservableCounter {
+		c, err := meter.Int64ObservableCounter("linkedlist."+name, metric.WithDescription(help))
+		errs = append(errs, err)
+		return c
+	}
+	gauge := func(name, help string) metric.Float64ObservableGauge {
+		g, err := meter.Float64ObservableGauge("linkedlist."+name, metric.WithDescription(help))
+		errs = append(errs, err)
+		return g
+	}
+
+	inserts := counter("inserts", "Number of nodes inserted into the list.")
+	deletes := counter("deletes", "Number of nodes deleted from the list.")
+	casAttempts := counter("cas.attempts", "Number of node state updates tried.")
+	casFailures := counter("cas.failures", "Number of node state updates failed due to concurrent changes.")
+	helps := counter("helps", "Number of removals completed on behalf of other goroutines.")
+	backlinkHops := counter("backlink.hops", "Number of backlinks followed from removed nodes.")
+	retries := counter("operation.retries", "Number of retries Insert and Delete calls made.")
+	retryBuckets := counter("operation.retries.bucket", "Number of Insert and Delete calls by number of retries.")
+	helpBuckets := counter("operation.helps.bucket", "Number of Insert and Delete calls by number of removals completed on behalf of other goroutines.")
+	casRatio := gauge("cas.failure_ratio", "Share of failed node state updates since the collector start.")
+	length := gauge("length_estimate", "Number of nodes inserted minus number of nodes deleted.")
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// Prepare attributes once, callback runs on every collection
+	listAttr := attribute.String("list", list)
+	attrs := metric.WithAttributes(listAttr)
+	bucketAttrs := make([]metric.ObserveOption, linkedlist.RetryBuckets)
+	for i := range bucketAttrs {
+		le := "+Inf"
+		if i < linkedlist.RetryBuckets-1 {
+			le = strconv.FormatUint(linkedlist.RetryBound(i), 10)
+		}
+		bucketAttrs[i] = metric.WithAttributes(listAttr, attribute.String("le", le))
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := stats.Snapshot()
+		o.ObserveInt64(inserts, int64(s.Inserts), attrs)
+		o.ObserveInt64(deletes, int64(s.Deletes), attrs)
+		o.ObserveInt64(casAttempts, int64(s.CASAttempts), attrs)
+		o.ObserveInt64(casFailures, int64(s.CASFailures), attrs)
+		o.ObserveInt64(helps, int64(s.Helps), attrs)
+		o.ObserveInt64(backlinkHops, int64(s.BacklinkHops), attrs)
+		o.ObserveInt64(retries, int64(s.Retries), attrs)
+		observeBuckets(o, retryBuckets, s.RetryHistogram, bucketAttrs)
+		observeBuckets(o, helpBuckets, s.HelpHistogram, bucketAttrs)
+		o.ObserveFloat64(casRatio, FailureRatio(s), attrs)
+		o.ObserveFloat64(length, float64(LengthEstimate(s)), attrs)
+		return nil
+	}, inserts, deletes, casAttempts, casFailures, helps, backlinkHops, retries, retryBuckets, helpBuckets, casRatio, length)
+}
+
+// observeBuckets reports histogram as cumulative counters per bucket
+func observeBuckets(o metric.Observer, c metric.Int64ObservableCounter, histogram [linkedlist.RetryBuckets]uint64, attrs []metric.ObserveOption) {
+	total := uint64(0)
+	for i, v := range histogram {
+		total += v
+		o.ObserveInt64(c, int64(total), attrs[i])
+	}
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xphoenix/linkedlist"
+)
+
+// Collector exports linkedlist.Stats of a single list as Prometheus metrics
+type Collector struct {
+	stats *linkedlist.Stats
+
+	inserts      *prometheus.Desc
+	deletes      *prometheus.Desc
+	casAttempts  *prometheus.Desc
+	casFailures  *prometheus.Desc
+	casRatio     *prometheus.Desc
+	helps        *prometheus.Desc
+	backlinkHops *prometheus.Desc
+	length       *prometheus.Desc
+	retries      *prometheus.Desc
+	helpCalls    *prometheus.Desc
+}
+
+// NewCollector creates Prometheus collector for the list with the given name
+func NewCollector(list string, stats *linkedlist.Stats) *Collector {
+	labels := prometheus.Labels{"list": list}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("linkedlist_"+name, help, nil, labels)
+	}
+
+	return &Collector{
+		stats:        stats,
+		inserts:      desc("inserts_total", "Number of nodes inserted into the list."),
+		deletes:      desc("deletes_total", "Number of nodes deleted from the list."),
+		casAttempts:  desc("cas_attempts_total", "Number of node state updates tried."),
+		casFailures:  desc("cas_failures_total", "Number of node state updates failed due to concurrent changes."),
+		casRatio:     desc("cas_failure_ratio", "Share of failed node state updates since the collector start."),
+		helps:        desc("helps_total", "Number of removals completed on behalf of other goroutines."),
+		backlinkHops: desc("backlink_hops_total", "Number of backlinks followed from removed nodes."),
+		length:       desc("length_estimate", "Number of nodes inserted minus number of nodes deleted."),
+		retries:      desc("operation_retries", "Number of retries Insert and Delete calls made."),
+		helpCalls:    desc("operation_helps", "Number of removals Insert and Delete calls completed on behalf of other goroutines."),
+	}
+}
+
+// Describe implements prometheus.Collector interface
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.inserts
+	ch <- c.deletes
+	ch <- c.casAttempts
+	ch <- c.casFailures
+	ch <- c.casRatio
+	ch <- c.helps
+	ch <- c.backlinkHops
+	ch <- c.length
+	ch <- c.retries
+	ch <- c.helpCalls
+}
+
+// Collect implements prometheus.Collector interface
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats.Snapshot()
+
+	counter := func(desc *prometheus.Desc, v uint64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(v))
+	}
+	counter(c.inserts, s.Inserts)
+	counter(c.deletes, s.Deletes)
+	counter(c.casAttempts, s.CASAttempts)
+	counter(c.casFailures, s.CASFailures)
+	counter(c.helps, s.Helps)
+	counter(c.backlinkHops, s.BacklinkHops)
+
+	ch <- prometheus.MustNewConstMetric(c.casRatio, prometheus.GaugeValue, FailureRatio(s))
+	ch <- prometheus.MustNewConstMetric(c.length, prometheus.GaugeValue, float64(LengthEstimate(s)))
+
+	buckets, total := cumulative(s.RetryHistogram)
+	ch <- prometheus.MustNewConstHistogram(c.retries, total, float64(s.Retries), buckets)
+
+	buckets, total = cumulative(s.HelpHistogram)
+	ch <- prometheus.MustNewConstHistogram(c.helpCalls, total, float64(s.OperationHelps), buckets)
+}
+
+// cumulative converts snapshot histogram to Prometheus buckets which are
+// cumulative while snapshot's are not. Last bucket has no bound and goes to
+// +Inf, so it is only counted in total
+func cumulative(histogram [linkedlist.RetryBuckets]uint64) (map[float64]uint64, uint64) {
+	buckets, total := make(map[float64]uint64, linkedlist.RetryBuckets-1), uint64(0)
+	for i, v := range histogram {
+		total += v
+		if i < linkedlist.RetryBuckets-1 {
+			buckets[float64(linkedlist.RetryBound(i))] = total
+		}
+	}
+	return buckets, total
+}
+
+// FailureRatio returns share of failed CAS attempts in the snapshot
+func FailureRatio(s linkedlist.StatsSnapshot) float64 {
+	if s.CASAttempts == 0 {
+		return 0
+	}
+	return float64(s.CASFailures) / float64(s.CASAttempts)
+}
+
+// LengthEstimate returns number of nodes inserted minus number of nodes
+// deleted according to the snapshot
+func LengthEstimate(s linkedlist.StatsSnapshot) int64 {
+	return int64(s.Inserts) - int64(s.Deletes)
+}
@@ -0,0 +1,45 @@
+package linkedlist
+
+// MultiNode is an object which could be a member of several lists at once.
+// Every list has own state slot selected by the list number, so the object is
+// inserted into and deleted from each list independently
+type MultiNode interface {
+	StateFor(list int) **State
+}
+
+// Membership is a view of MultiNode as a node of the given list. Membership is
+// compared by value, so views of the same object and list created separately
+// are equal and could be used to find and delete object from the list. Nodes
+// returned by list operations are Membership values, use Node field to get
+// object back
+type Membership struct {
+	Node MultiNode
+	List int
+}
+
+// Member returns node of the object in the given list
+func Member(node MultiNode, list int) Node {
+	return Membership{Node: node, List: list}
+}
+
+// State implements Node interface
+func (m Membership) State() **State {
+	return m.Node.StateFor(m.List)
+}
+
+// Links is a set of state slots to embed into MultiNode implementations
+type Links []*State
+
+// NewLinks creates state slots for the given number of lists
+func NewLinks(lists int) Links {
+	l := make(Links, lists)
+	for i := range l {
+		l[i] = &State{}
+	}
+	return l
+}
+
+// StateFor implements MultiNode interface
+func (l Links) StateFor(list int) **State {
+	return &l[list]
+}
@@ -0,0 +1,139 @@
+package linkedlist
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Observer receives notifications about structural changes of the list. Each
+// event is reported exactly once by the goroutine which wins the corresponding
+// state update, no matter how many goroutines help to complete an operation.
+//
+// Callbacks are invoked synchronously just after the change becomes visible to
+// other goroutines, so keep them short. Events of the same node are reported in
+// order OnInsert, OnLogicalDelete, OnUnlink only if they are reported by the
+// same goroutine, events reported by different goroutines are not ordered
+type Observer interface {
+	// OnInsert reports that node has been linked after pred
+	OnInsert(pred, node Node)
+	// OnLogicalDelete reports that node has been deleted from the list, since
+	// that moment node is not a part of the list even if still reachable
+	OnLogicalDelete(node Node)
+	// OnUnlink reports that node has been physically unlinked from pred
+	OnUnlink(pred, node Node)
+}
+
+// Sequencer is an Observer which numbers insert and logical delete events in
+// linearization order. Operation takes a ticket just before its linearizing
+// state update, after the state to replace has been loaded. So an operation
+// which depends on the result of another one always gets greater ticket.
+//
+// Ticket of a successful update is reported with the event, ticket of a failed
+// one is canceled. Every ticket taken is either reported or canceled exactly
+// once. Sequencer must be installed as the node's observer directly, inside
+// Observers it gets events without tickets
+type Sequencer interface {
+	Observer
+	// Ticket reserves the next ticket
+	Ticket() uint64
+	// Cancel releases ticket of a failed update
+	Cancel(ticket uint64)
+	// OnInsertAt is OnInsert of the update with the given ticket
+	OnInsertAt(ticket uint64, pred, node Node)
+	// OnLogicalDeleteAt is OnLogicalDelete of the update with the given ticket
+	OnLogicalDeleteAt(ticket uint64, node Node)
+}
+
+// ObservedNode is implemented by nodes which report events to a list specific
+// observer rather than to the global one
+type ObservedNode interface {
+	Node
+	Observer() Observer
+}
+
+// Observers is an Observer which passes events to all observers in order
+type Observers []Observer
+
+// OnInsert implements Observer interface
+func (o Observers) OnInsert(pred, node Node) {
+	for _, observer := range o {
+		observer.OnInsert(pred, node)
+	}
+}
+
+// OnLogicalDelete implements Observer interface
+func (o Observers) OnLogicalDelete(node Node) {
+	for _, observer := range o {
+		observer.OnLogicalDelete(node)
+	}
+}
+
+// OnUnlink implements Observer interface
+func (o Observers) OnUnlink(pred, node Node) {
+	for _, observer := range o {
+		observer.OnUnlink(pred, node)
+	}
+}
+
+// observerBox allows to store interface value by a single pointer
+type observerBox struct {
+	observer Observer
+}
+
+// globalObserver is a box with observer used for nodes that do not implement
+// ObservedNode
+var globalObserver unsafe.Pointer
+
+// SetObserver installs global observer and returns previous one. Passing nil
+// disables global notifications
+func SetObserver(o Observer) Observer {
+	var box unsafe.Pointer
+	if o != nil {
+		box = unsafe.Pointer(&observerBox{observer: o})
+	}
+
+	prev := (*observerBox)(atomic.SwapPointer(&globalObserver, box))
+	if prev == nil {
+		return nil
+	}
+	return prev.observer
+}
+
+// observerOf returns observer events of the given node should be reported to
+// or nil if notifications are disabled
+func observerOf(node Node) Observer {
+	if on, ok := node.(ObservedNode); ok {
+		return on.Observer()
+	}
+
+	box := (*observerBox)(atomic.LoadPointer(&globalObserver))
+	if box == nil {
+		return nil
+	}
+	return box.observer
+}
+
+// sequencerOf returns observer as Sequencer or nil if it is not a sequencer
+func sequencerOf(o Observer) Sequencer {
+	seq, _ := o.(Sequencer)
+	return seq
+}
+
+// notifyInsert reports insert done by update with the given ticket
+func notifyInsert(o Observer, ticket uint64, pred, node Node) {
+	if seq, ok := o.(Sequencer); ok {
+		seq.OnInsertAt(ticket, pred, node)
+	} else if o != nil {
+		o.OnInsert(pred, node)
+	}
+}
+
+// notifyLogicalDelete reports logical delete done by update with the given
+// ticket
+func notifyLogicalDelete(o Observer, ticket uint64, node Node) {
+	if seq, ok := o.(Sequencer); ok {
+		seq.OnLogicalDeleteAt(ticket, node)
+	} else if o != nil {
+		o.OnLogicalDelete(node)
+	}
+}
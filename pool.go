@@ -0,0 +1,220 @@
+package linkedlist
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+const (
+	// Number of retired objects a guard collects before it tries to advance
+	// epoch and reclaim them
+	reclaimThreshold = 64
+
+	// Maximum number of free states a guard keeps, the rest goes to GC
+	maxFreeStates = 1024
+)
+
+// Pool recycles State objects replaced by list updates and nodes deleted from
+// the list, so operations do not need to allocate in a steady state.
+//
+// Object replaced in the list could still be read by concurrent operations, so
+// pool reuses it only after all of them finish. That is done with epoch based
+// reclamation: every operation runs inside a Guard, object retired in epoch E
+// is reused once global epoch reaches E+2, which is only possible when all
+// guards entered in epoch E or before have exited.
+//
+// When a list is used with a pool, every access to it must be done inside a
+// guard, including Snapshot and reading states returned by LoadState. Nodes
+// and states must not be kept after guard exit
+type Pool struct {
+	epoch  uint64
+	guards unsafe.Pointer
+}
+
+// Guard is an operation context of the pool. Guard is owned by a single
+// goroutine between Enter and Exit, it collects objects retired by operations
+// and keeps objects ready to be reused
+type Guard struct {
+	pool *Pool
+	next *Guard
+
+	// announced is an epoch guard has been entered in shifted left by one
+	// with the lowest bit set while guard is active
+	announced uint64
+
+	// retired objects split by epoch modulo 3: objects of the current epoch,
+	// previous one and the one before it which is safe to reuse
+	retired      [3][]*State
+	retiredNodes [3][]retiredNode
+	retiredEpoch [3]uint64
+	retiredCount int
+
+	free []*State
+}
+
+// retiredNode is a node waiting to be reused along with reuse callback
+type retiredNode struct {
+	node  Node
+	reuse func(Node)
+}
+
+// NewPool creates a new pool
+func NewPool() *Pool {
+	return &Pool{}
+}
+
+// Enter starts an operation context. Guard must be exited by the same
+// goroutine with Exit
+func (p *Pool) Enter() *Guard {
+	epoch := atomic.LoadUint64(&p.epoch)
+
+	// Reuse inactive guard if any, guards are never removed from the registry
+	for g := (*Guard)(atomic.LoadPointer(&p.guards)); g != nil; g = g.next {
+		announced := atomic.LoadUint64(&g.announced)
+		if announced&1 == 0 && atomic.CompareAndSwapUint64(&g.announced, announced, epoch<<1|1) {
+			return g
+		}
+	}
+
+	g := &Guard{pool: p, announced: epoch<<1 | 1}
+	for {
+		head := atomic.LoadPointer(&p.guards)
+		g.next = (*Guard)(head)
+		if atomic.CompareAndSwapPointer(&p.guards, head, unsafe.Pointer(g)) {
+			return g
+		}
+	}
+}
+
+// Exit finishes operation context
+func (g *Guard) Exit() {
+	if g.retiredCount >= reclaimThreshold {
+		g.pool.tryAdvance()
+	}
+	atomic.StoreUint64(&g.announced, atomic.LoadUint64(&g.announced)&^1)
+}
+
+// tryAdvance moves global epoch forward if all active guards have seen the
+// current one
+func (p *Pool) tryAdvance() {
+	epoch := atomic.LoadUint64(&p.epoch)
+	for g := (*Guard)(atomic.LoadPointer(&p.guards)); g != nil; g = g.next {
+		announced := atomic.LoadUint64(&g.announced)
+		if announced&1 == 1 && announced>>1 != epoch {
+			return
+		}
+	}
+	atomic.CompareAndSwapUint64(&p.epoch, epoch, epoch+1)
+}
+
+// NewState returns a state to use with weak operations. State is taken from
+// the pool if possible
+func (g *Guard) NewState() *State {
+	return g.newState()
+}
+
+// newState returns zero state from the guard's free list or allocates a new
+// one. It is safe to call newState on nil guard
+func (g *Guard) newState() *State {
+	if g == nil || len(g.free) == 0 {
+		return &State{}
+	}
+
+	s := g.free[len(g.free)-1]
+	g.free = g.free[:len(g.free)-1]
+	return s
+}
+
+// recycle returns state which has never been published to the free list. It
+// is safe to call recycle on nil guard
+func (g *Guard) recycle(s *State) {
+	if g == nil || len(g.free) >= maxFreeStates {
+		return
+	}
+
+	*s = State{}
+	g.free = append(g.free, s)
+}
+
+// retire puts state replaced in the list aside until it is safe to reuse. It
+// is safe to call retire on nil guard
+func (g *Guard) retire(s *State) {
+	if g == nil {
+		return
+	}
+
+	bucket := g.bucket()
+	g.retired[bucket] = append(g.retired[bucket], s)
+	g.retiredCount++
+}
+
+// RetireNode hands node deleted from the list over to the pool. Once no
+// operation could see node anymore pool installs a fresh state into the node
+// and calls reuse, so it could be inserted again. Node must be deleted and
+// unlinked, that is true once Delete returns
+func (g *Guard) RetireNode(n Node, reuse func(Node)) {
+	bucket := g.bucket()
+	g.retiredNodes[bucket] = append(g.retiredNodes[bucket], retiredNode{node: n, reuse: reuse})
+	g.retiredCount++
+}
+
+// bucket returns retired bucket of the current epoch. Bucket is reclaimed
+// first if it holds objects retired 3 epochs ago or earlier
+func (g *Guard) bucket() int {
+	epoch := atomic.LoadUint64(&g.pool.epoch)
+	bucket := int(epoch % 3)
+	if g.retiredEpoch[bucket] != epoch {
+		g.reclaim(bucket)
+		g.retiredEpoch[bucket] = epoch
+	}
+	return bucket
+}
+
+// reclaim reuses objects of the bucket. Must be called only if bucket's epoch
+// is at least 2 epochs behind the global one
+func (g *Guard) reclaim(bucket int) {
+	for _, s := range g.retired[bucket] {
+		g.recycle(s)
+	}
+	g.retiredCount -= len(g.retired[bucket])
+	g.retired[bucket] = g.retired[bucket][:0]
+
+	for _, r := range g.retiredNodes[bucket] {
+		old := *r.node.State()
+		*r.node.State() = g.newState()
+		g.recycle(old)
+		r.reuse(r.node)
+	}
+	g.retiredCount -= len(g.retiredNodes[bucket])
+	g.retiredNodes[bucket] = g.retiredNodes[bucket][:0]
+}
+
+// Insert is Insert which takes states from the pool
+func (g *Guard) Insert(start, new Node) (Node, bool) {
+	return insert(g, start, new)
+}
+
+// InsertBefore is InsertBefore which takes states from the pool
+func (g *Guard) InsertBefore(head, target, new Node) (Node, bool) {
+	return insertBefore(g, head, target, new)
+}
+
+// WeakInsert is WeakInsert which retires replaced state to the pool
+func (g *Guard) WeakInsert(left, right Node, update *State, new Node) (Node, bool) {
+	return weakInsert(g, left, right, update, new, nil)
+}
+
+// Delete is Delete which takes states from the pool
+func (g *Guard) Delete(start, delNode Node) (Node, bool, bool) {
+	return deleteNode(g, start, delNode)
+}
+
+// WeakDelete is WeakDelete which retires replaced states to the pool
+func (g *Guard) WeakDelete(left, right Node, update *State) (Node, bool, bool) {
+	return weakDelete(g, left, right, update, nil)
+}
+
+// Next is Next which takes states from the pool
+func (g *Guard) Next(start Node) Node {
+	return next(g, start)
+}
@@ -0,0 +1,153 @@
+package linkedlist
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Ring is a circular list: the last node links back to a sentinel node, so
+// there is no nil terminator. Ring is intended for round-robin rotation over
+// a concurrent set of members, see Rotate and Cursor.
+//
+// Ring uses the same FREEZE/DELETE protocol as lists and package level Insert
+// and Next could be used with its nodes. Sentinel is never deleted, so freezed
+// chains always end before the sentinel and removal of the last member links
+// its predecessor back to the sentinel. Scans stop once they wrap around the
+// sentinel: Snapshot, Len and Encode stop at their head, so they work with the
+// ring if given its sentinel.
+//
+// Functions which scan until nil terminator must not be used with rings:
+// Delete, DeleteCtx, DeleteOf and InsertBefore never return for a node which
+// is not in the ring, use Ring.Delete instead. Range never ends on a ring
+type Ring struct {
+	head Link
+
+	// pos is a position shared by Rotate callers
+	pos unsafe.Pointer
+}
+
+// ringPos boxes position of the shared cursor, so it could be updated by CAS
+type ringPos struct {
+	node Node
+}
+
+// NewRing creates an empty ring
+func NewRing() *Ring {
+	r := &Ring{}
+	r.head.state = &State{Next: &r.head}
+	r.pos = unsafe.Pointer(&ringPos{node: &r.head})
+	return r
+}
+
+// Sentinel returns sentinel node of the ring
+func (r *Ring) Sentinel() Node {
+	return &r.head
+}
+
+// Insert adds member to the ring just after the sentinel
+func (r *Ring) Insert(member Node) {
+	Insert(&r.head, member)
+}
+
+// InsertAfter adds member to the ring just after mark. If mark has been deleted
+// member is inserted after the closest alive predecessor of mark
+func (r *Ring) InsertAfter(mark, member Node) {
+	Insert(mark, member)
+}
+
+// Delete removes member from the ring, see Delete for returned flags. Sentinel
+// is not a member and could not be deleted
+func (r *Ring) Delete(member Node) (bool, bool) {
+	if member == Node(&r.head) {
+		return false, false
+	}
+
+	update, helped := &State{}, 0
+	var left Node = &r.head
+	right := LoadState(left).Next
+	for attempt := 0; ; attempt++ {
+		// Looking for the member, sentinel means the whole ring is scanned
+		for right != member {
+			if right == Node(&r.head) {
+				observeOp(&r.head, attempt, helped)
+				return false, false
+			}
+
+			left, right = right, LoadState(right).Next
+		}
+
+		p, suc, byme := weakDelete(nil, left, right, update, &helped)
+		if suc {
+			observeOp(&r.head, attempt, helped)
+			return suc, byme
+		}
+
+		// Failed to delete, so start now points to the new predecessor
+		left, right = p, LoadState(p).Next
+	}
+}
+
+// Next returns member after the given node skipping the sentinel, so the ring
+// wraps around. Function returns nil if ring is empty. Given node might be
+// removed, then traversal continues from the node it has been linked to
+func (r *Ring) Next(node Node) Node {
+	next := Next(node)
+	if next == Node(&r.head) {
+		next = Next(&r.head)
+	}
+	if next == Node(&r.head) {
+		return nil
+	}
+	return next
+}
+
+// Rotate advances position shared by all callers to the next member and
+// returns it, so concurrent callers get members in round-robin. Function
+// returns nil if ring is empty
+func (r *Ring) Rotate() Node {
+	for {
+		cur := atomic.LoadPointer(&r.pos)
+		next := r.Next((*ringPos)(cur).node)
+		if next == nil {
+			return nil
+		}
+		if !atomic.CompareAndSwapPointer(&r.pos, cur, unsafe.Pointer(&ringPos{node: next})) {
+			continue
+		}
+
+		// Member might be removed after Next returned it, skip it then
+		if !LoadState(next).IsRemoved() {
+			return next
+		}
+	}
+}
+
+// Cursor is a private round-robin position in the ring. Cursor must not be
+// used concurrently
+type Cursor struct {
+	ring *Ring
+	cur  Node
+}
+
+// Cursor creates a new cursor positioned at the sentinel
+func (r *Ring) Cursor() *Cursor {
+	return &Cursor{ring: r, cur: &r.head}
+}
+
+// NextMember moves cursor to the next alive member and returns it, wrapping
+// around the ring. Function returns nil if ring is empty
+func (c *Cursor) NextMember() Node {
+	for {
+		next := c.ring.Next(c.cur)
+		if next == nil {
+			c.cur = &c.ring.head
+			return nil
+		}
+		c.cur = next
+
+		// Member might be removed after Next returned it, skip it then
+		if !LoadState(next).IsRemoved() {
+			return next
+		}
+	}
+}
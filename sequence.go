@@ -0,0 +1,92 @@
+package linkedlist
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// sequenceWindow is a number of tickets which could be pending at once. Must
+// be a power of two
+const sequenceWindow = 1024
+
+// sequence applies outcomes of Sequencer tickets in ticket order. Outcome is
+// stored by the goroutine which got the ticket and applied by whichever
+// goroutine finds it ready, so goroutines never wait for each other unless
+// sequenceWindow tickets are pending
+type sequence struct {
+	// tickets is the next ticket to give out, next is the first ticket which
+	// is not applied yet
+	tickets uint64
+	next    uint64
+	slots   [sequenceWindow]sequenceSlot
+
+	// mu is held while outcomes are applied, apply is called for every not
+	// canceled event in ticket order
+	mu    sync.Mutex
+	apply func(Event)
+}
+
+// sequenceSlot keeps outcome of a ticket until it is applied
+type sequenceSlot struct {
+	// ready is the ticket plus one once outcome is stored
+	ready    uint64
+	canceled bool
+	event    Event
+}
+
+// ticket reserves the next ticket
+func (s *sequence) ticket() uint64 {
+	return atomic.AddUint64(&s.tickets, 1) - 1
+}
+
+// resolve stores outcome of the ticket and applies all outcomes which are
+// ready
+func (s *sequence) resolve(ticket uint64, canceled bool, e Event) {
+	// Slot is still occupied by the ticket sequenceWindow before, wait until
+	// it is applied
+	for ticket-atomic.LoadUint64(&s.next) >= sequenceWindow {
+		runtime.Gosched()
+	}
+
+	slot := &s.slots[ticket&(sequenceWindow-1)]
+	slot.canceled, slot.event = canceled, e
+	atomic.StoreUint64(&slot.ready, ticket+1)
+	s.drain()
+}
+
+// ready reports whether outcome of the next ticket is stored
+func (s *sequence) ready() bool {
+	next := atomic.LoadUint64(&s.next)
+	return atomic.LoadUint64(&s.slots[next&(sequenceWindow-1)].ready) == next+1
+}
+
+// drain applies outcomes in ticket order while they are ready. Goroutine which
+// fails to lock the sequence leaves the work to the lock holder, which checks
+// for ready outcomes once more after unlock
+func (s *sequence) drain() {
+	for s.ready() && s.mu.TryLock() {
+		for s.ready() {
+			next := atomic.LoadUint64(&s.next)
+			slot := &s.slots[next&(sequenceWindow-1)]
+			if !slot.canceled {
+				s.apply(slot.event)
+			}
+			slot.event = Event{}
+			atomic.StoreUint64(&s.next, next+1)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// lock stops outcomes from being applied
+func (s *sequence) lock() {
+	s.mu.Lock()
+}
+
+// unlock releases the sequence and applies outcomes which got ready while it
+// has been locked
+func (s *sequence) unlock() {
+	s.mu.Unlock()
+	s.drain()
+}
@@ -0,0 +1,93 @@
+package linkedlist
+
+import "runtime"
+
+// shardHead is a head of a shard padded to a cache line, so CAS on one head
+// does not invalidate the others
+type shardHead struct {
+	Link
+	_ [56]byte
+}
+
+// ShardedList spreads nodes over several lists, so concurrent inserts do not
+// contend on a single head node. It is intended for append heavy workloads
+// like telemetry collectors where many goroutines add nodes and a reader
+// periodically travels over all of them.
+//
+// Ordering trade-off: there is no order between nodes of different shards.
+// Each travels shards one by one and within a shard newer nodes come first,
+// so merged iteration is neither insertion order nor its reverse
+type ShardedList struct {
+	shards []shardHead
+}
+
+// NewShardedList creates a list with given number of shards rounded up to a
+// power of two. Zero or negative number means GOMAXPROCS shards
+func NewShardedList(shards int) *ShardedList {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+
+	l := &ShardedList{shards: make([]shardHead, n)}
+	for i := range l.shards {
+		l.shards[i].state = &State{}
+	}
+	return l
+}
+
+// Shards returns number of shards
+func (l *ShardedList) Shards() int {
+	return len(l.shards)
+}
+
+// Head returns head node of the shard, so package level functions could be
+// used with it
+func (l *ShardedList) Head(shard int) Node {
+	return &l.shards[shard]
+}
+
+// Insert adds node to the shard selected by hash of the node
+func (l *ShardedList) Insert(node Node) {
+	Insert(&l.shards[shardOf(node, len(l.shards))], node)
+}
+
+// InsertHint adds node to the shard selected by hint, for example an id of the
+// worker goroutine. Workers with different hints do not contend with each
+// other. Delete of such nodes might need to scan several shards
+func (l *ShardedList) InsertHint(hint uint64, node Node) {
+	Insert(&l.shards[hint&uint64(len(l.shards)-1)], node)
+}
+
+// Delete removes node from the list, see Delete for returned flags. Shard
+// selected by hash of the node is searched first, then all the others
+func (l *ShardedList) Delete(node Node) (bool, bool) {
+	first := shardOf(node, len(l.shards))
+	for i := 0; i < len(l.shards); i++ {
+		shard := (first + i) & (len(l.shards) - 1)
+		if _, removed, byme := Delete(&l.shards[shard], node); removed {
+			return removed, byme
+		}
+	}
+	return false, false
+}
+
+// Each calls fn for every alive node of the list until fn returns false. See
+// ShardedList for order of nodes
+func (l *ShardedList) Each(fn func(Node) bool) {
+	for i := range l.shards {
+		for cur := Next(&l.shards[i]); cur != nil; cur = Next(cur) {
+			// Node might be deleted after Next returned it
+			if LoadState(cur).IsRemoved() {
+				continue
+			}
+			if !fn(cur) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package linkedlist
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Number of stripes in a Size counter, must be a power of two
+const sizeStripes = 16
+
+// sizeStripe is a single counter padded to a cache line
+type sizeStripe struct {
+	n int64
+	_ [56]byte
+}
+
+// Size is a Sequencer which tracks number of nodes in the list. Every insert
+// and every logical delete is reported exactly once, by the goroutine which
+// inserted node and by the goroutine which got "deleted by me" flag.
+//
+// ApproxLen is served by striped counters updated as soon as operation
+// reports, so concurrent operations on different nodes do not contend on the
+// counter. Len is linearizable: changes are also applied to an exact counter
+// in ticket order, see Sequencer, and Len returns its value once all changes
+// which took tickets before the call are applied.
+//
+// Install Size as the list observer directly, inside Observers it gets events
+// without tickets and Len loses linearizability. Counter starts from zero, so
+// it should be installed on an empty list. Raw MCAS updates bypass observers,
+// nodes linked or unlinked by them are not counted. Tx.Insert and Tx.Delete
+// are reported on commit and counted as any other operation
+type Size struct {
+	stripes [sizeStripes]sizeStripe
+
+	// order applies changes to n in ticket order, n is guarded by order's
+	// lock
+	order sequence
+	n     int64
+}
+
+// NewSize creates new zero size counter
+func NewSize() *Size {
+	s := &Size{}
+	s.order.apply = s.apply
+	return s
+}
+
+// OnInsert implements Observer interface
+func (s *Size) OnInsert(pred, node Node) {
+	s.OnInsertAt(s.Ticket(), pred, node)
+}
+
+// OnLogicalDelete implements Observer interface
+func (s *Size) OnLogicalDelete(node Node) {
+	s.OnLogicalDeleteAt(s.Ticket(), node)
+}
+
+// OnUnlink implements Observer interface
+func (s *Size) OnUnlink(pred, node Node) {
+}
+
+// Ticket implements Sequencer interface
+func (s *Size) Ticket() uint64 {
+	return s.order.ticket()
+}
+
+// Cancel implements Sequencer interface
+func (s *Size) Cancel(ticket uint64) {
+	s.order.resolve(ticket, true, Event{})
+}
+
+// OnInsertAt implements Sequencer interface
+func (s *Size) OnInsertAt(ticket uint64, pred, node Node) {
+	atomic.AddInt64(&s.stripes[shardOf(node, sizeStripes)].n, 1)
+	s.order.resolve(ticket, false, Event{Kind: EventInsert})
+}
+
+// OnLogicalDeleteAt implements Sequencer interface
+func (s *Size) OnLogicalDeleteAt(ticket uint64, node Node) {
+	atomic.AddInt64(&s.stripes[shardOf(node, sizeStripes)].n, -1)
+	s.order.resolve(ticket, false, Event{Kind: EventDelete})
+}
+
+// apply updates exact counter in ticket order
+func (s *Size) apply(e Event) {
+	if e.Kind == EventInsert {
+		s.n++
+	} else {
+		s.n--
+	}
+}
+
+// ApproxLen returns number of nodes in the list. Stripes are summed up one by
+// one, so under concurrent modifications result might be not a size list ever
+// had, but it is exact once modifications stop
+func (s *Size) ApproxLen() int64 {
+	var n int64
+	for i := range s.stripes {
+		n += atomic.LoadInt64(&s.stripes[i].n)
+	}
+	return n
+}
+
+// Len returns number of nodes in the list. Result is linearizable: it counts
+// every change completed before the call and no change started after it.
+// Function waits for operations which took tickets before the call to report,
+// it takes O(1) unless one of them has stalled
+func (s *Size) Len() int64 {
+	last := atomic.LoadUint64(&s.order.tickets)
+	for {
+		s.order.lock()
+		applied, n := atomic.LoadUint64(&s.order.next), s.n
+		s.order.unlock()
+
+		if applied >= last {
+			return n
+		}
+		runtime.Gosched()
+	}
+}
+
+// Len returns number of nodes after head by walking the list, it does not need
+// size counter. Result is linearizable: list had exactly that many nodes at
+// some moment during the call. Len has to walk the list at least twice and
+// retries while the list changes, see Snapshot, use Size for lists which are
+// modified all the time
+func Len(head Node) int {
+	return Snapshot(head).Len()
+}
@@ -0,0 +1,109 @@
+package linkedlist
+
+import "encoding/json"
+
+// ListSnapshot is an immutable point in time view of the list. It holds nodes
+// which were alive in the list at some moment between the Snapshot call and
+// its return, in order
+type ListSnapshot struct {
+	nodes []Node
+}
+
+// Snapshot takes consistent view of the list after the given head node. Unlike
+// traversal with Next snapshot never sees a node inserted after or misses one
+// deleted before the moment it represents.
+//
+// Snapshot never blocks writers: it collects the list twice and succeeds when
+// both collections see exactly the same node states. As every update installs
+// a new State, equal states mean nothing has been changed in between. Under
+// constant modifications of the list it could retry for a long time, see
+// TrySnapshot to limit it. Snapshot of a Ring must be taken from its sentinel
+func Snapshot(head Node) *ListSnapshot {
+	s, _ := TrySnapshot(head, 0)
+	return s
+}
+
+// TrySnapshot takes consistent view of the list making at most given number of
+// attempts, zero means no limit. Function returns false if every attempt
+// detects concurrent changes
+func TrySnapshot(head Node, attempts int) (*ListSnapshot, bool) {
+	nodes, states := collect(head)
+	for i := 0; attempts == 0 || i < attempts; i++ {
+		nextNodes, nextStates := collect(head)
+		if sameStates(states, nextStates) {
+			return &ListSnapshot{nodes: alive(nodes, states)}, true
+		}
+		nodes, states = nextNodes, nextStates
+	}
+	return nil, false
+}
+
+// collect walks raw links from head without helping anybody and records every
+// reachable node along with the state it has been seen in. Walk stops at nil
+// or once it gets back to head, which is the end of a Ring
+func collect(head Node) (nodes []Node, states []*State) {
+	for cur := head; cur != nil; {
+		state := LoadState(cur)
+		nodes, states = append(nodes, cur), append(states, state)
+		if cur = state.Next; cur == head {
+			break
+		}
+	}
+	return nodes, states
+}
+
+// sameStates checks whether two collections have seen the same state pointers
+func sameStates(a, b []*State) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// alive filters out head and all logically deleted nodes from the collection:
+// nodes marked as removed and nodes with freezed predecessor
+func alive(nodes []Node, states []*State) []Node {
+	result := make([]Node, 0, len(nodes))
+	for i := 1; i < len(nodes); i++ {
+		if states[i].IsRemoved() || states[i-1].IsFreezed() {
+			continue
+		}
+		result = append(result, nodes[i])
+	}
+	return result
+}
+
+// Len returns number of nodes in the snapshot
+func (s *ListSnapshot) Len() int {
+	return len(s.nodes)
+}
+
+// At returns i-th node of the snapshot
+func (s *ListSnapshot) At(i int) Node {
+	return s.nodes[i]
+}
+
+// Each calls fn for every node of the snapshot in order until fn returns false
+func (s *ListSnapshot) Each(fn func(Node) bool) {
+	for _, n := range s.nodes {
+		if !fn(n) {
+			return
+		}
+	}
+}
+
+// Nodes returns copy of the snapshot nodes
+func (s *ListSnapshot) Nodes() []Node {
+	return append([]Node(nil), s.nodes...)
+}
+
+// MarshalJSON implements json.Marshaler interface. Snapshot is encoded as an
+// array of its nodes
+func (s *ListSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.nodes)
+}
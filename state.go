@@ -30,6 +30,9 @@ type State struct {
 	Next  Node
 	Back  Node
 	Flags Flags
+
+	// desc is set for placeholder states installed by MCAS
+	desc *mcasDescriptor
 }
 
 // IsRemoved returns true if current state is for a node that is logically removed
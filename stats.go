@@ -0,0 +1,216 @@
+package linkedlist
+
+import (
+	"expvar"
+	"math/rand"
+	"sync/atomic"
+	"unsafe"
+)
+
+const (
+	// Number of shards in a Stats collector, must be a power of two
+	statsShards = 16
+
+	// RetryBuckets is a number of buckets in operation retries histogram
+	RetryBuckets = 8
+)
+
+// counter identifies one of the Stats counters
+type counter int
+
+const (
+	casAttempts counter = iota
+	casFailures
+	helps
+	backlinkHops
+	inserts
+	deletes
+	retries
+	operationHelps
+	retryBucket
+	helpBucket   = retryBucket + RetryBuckets
+	counterCount = helpBucket + RetryBuckets
+)
+
+// statsShard is a set of counters padded to a cache line, so concurrent
+// updates of different shards do not fight for the same line
+type statsShard struct {
+	counters [counterCount]uint64
+	_        [64 - counterCount*8%64]byte
+}
+
+// Stats collects operation counters of the list. Every update goes to a random
+// shard, so goroutines do not contend on the collector even when all of them
+// work on the same hot node
+//
+// Collector could be installed globally with SetStats or per list by
+// implementing StatsNode interface on the list's nodes
+type Stats struct {
+	shards [statsShards]statsShard
+}
+
+// StatsSnapshot is a point in time copy of Stats counters
+type StatsSnapshot struct {
+	// CASAttempts is a number of state updates tried
+	CASAttempts uint64
+	// CASFailures is a number of state updates failed due to concurrent changes
+	CASFailures uint64
+	// Helps is a number of times operation completes removal started by others
+	Helps uint64
+	// BacklinkHops is a number of backlinks followed from removed nodes
+	BacklinkHops uint64
+	// Inserts is a number of nodes inserted
+	Inserts uint64
+	// Deletes is a number of nodes deleted
+	Deletes uint64
+	// Retries is a total number of retries done by Insert and Delete
+	Retries uint64
+	// RetryHistogram is a distribution of Insert and Delete calls by number of
+	// retries they did. Bucket i counts calls with retries up to RetryBound(i)
+	// and more than the bound of the previous bucket, last bucket has no bound
+	RetryHistogram [RetryBuckets]uint64
+	// OperationHelps is a total number of removals Insert and Delete completed
+	// on behalf of other threads, unlike Helps it does not count traversals
+	OperationHelps uint64
+	// HelpHistogram is a distribution of Insert and Delete calls by number of
+	// removals they completed on behalf of other threads. Buckets have the same
+	// bounds as RetryHistogram ones
+	HelpHistogram [RetryBuckets]uint64
+}
+
+// StatsNode is implemented by nodes which report operations into a list
+// specific collector rather than to the global one
+type StatsNode interface {
+	Node
+	Stats() *Stats
+}
+
+// globalStats is a collector used for nodes that do not implement StatsNode
+var globalStats unsafe.Pointer
+
+// statsCreated is set once the first collector is created. Until then
+// operations skip statistics after a single load, without looking up collector
+// of the node
+var statsCreated uint32
+
+// NewStats creates new empty collector. Collectors must be created with
+// NewStats, zero Stats is not seen by operations
+func NewStats() *Stats {
+	atomic.StoreUint32(&statsCreated, 1)
+	return &Stats{}
+}
+
+// SetStats installs global collector and returns previous one. Passing nil
+// disables global statistics
+func SetStats(s *Stats) *Stats {
+	return (*Stats)(atomic.SwapPointer(&globalStats, unsafe.Pointer(s)))
+}
+
+// statsOf returns collector operation on the given node should report to or
+// nil if statistics is disabled
+func statsOf(node Node) *Stats {
+	if atomic.LoadUint32(&statsCreated) == 0 {
+		return nil
+	}
+	if sn, ok := node.(StatsNode); ok {
+		return sn.Stats()
+	}
+	return (*Stats)(atomic.LoadPointer(&globalStats))
+}
+
+// add increments counter in a random shard. It is safe to call add on nil
+// collector
+func (s *Stats) add(c counter) {
+	s.addN(c, 1)
+}
+
+// addN adds delta to counter in a random shard
+func (s *Stats) addN(c counter, delta uint64) {
+	if s == nil {
+		return
+	}
+
+	shard := &s.shards[rand.Uint32()&(statsShards-1)]
+	atomic.AddUint64(&shard.counters[c], delta)
+}
+
+// shardOf spreads nodes between given power of two number of shards. Address
+// of the node's state slot is used as a cheap hash
+func shardOf(node Node, shards int) int {
+	h := uint64(uintptr(unsafe.Pointer(node.State()))) * 0x9E3779B97F4A7C15
+	return int(h>>32) & (shards - 1)
+}
+
+// count increments counter of the collector given node reports to
+func count(node Node, c counter) {
+	statsOf(node).add(c)
+}
+
+// observeOp records number of retries and helps done by an operation started
+// from the given node
+func observeOp(node Node, retried, helped int) {
+	stats := statsOf(node)
+	if stats == nil {
+		return
+	}
+
+	stats.add(retryBucket + bucketOf(retried))
+	stats.add(helpBucket + bucketOf(helped))
+	stats.addN(retries, uint64(retried))
+	stats.addN(operationHelps, uint64(helped))
+}
+
+// bucketOf returns histogram bucket value n falls into
+func bucketOf(n int) counter {
+	bucket := 0
+	for bucket < RetryBuckets-1 && uint64(n) > RetryBound(bucket) {
+		bucket++
+	}
+	return counter(bucket)
+}
+
+// addHelps adds n to helps counter of an operation if it is tracked
+func addHelps(helped *int, n int) {
+	if helped != nil {
+		*helped += n
+	}
+}
+
+// RetryBound returns inclusive upper bound of retries histogram bucket: 0, 1,
+// 3, 7 and so on
+func RetryBound(bucket int) uint64 {
+	return 1<<uint(bucket) - 1
+}
+
+// Snapshot sums up all shards. Counters are read one by one, so snapshot
+// taken under load is not an atomic view but every counter is accurate
+func (s *Stats) Snapshot() StatsSnapshot {
+	var sum [counterCount]uint64
+	for i := range s.shards {
+		for c := range sum {
+			sum[c] += atomic.LoadUint64(&s.shards[i].counters[c])
+		}
+	}
+
+	snapshot := StatsSnapshot{
+		CASAttempts:    sum[casAttempts],
+		CASFailures:    sum[casFailures],
+		Helps:          sum[helps],
+		BacklinkHops:   sum[backlinkHops],
+		Inserts:        sum[inserts],
+		Deletes:        sum[deletes],
+		Retries:        sum[retries],
+		OperationHelps: sum[operationHelps],
+	}
+	copy(snapshot.RetryHistogram[:], sum[retryBucket:helpBucket])
+	copy(snapshot.HelpHistogram[:], sum[helpBucket:])
+	return snapshot
+}
+
+// Publish exports collector's snapshot as expvar variable with the given name.
+// Like expvar.Publish it panics if the name is already registered
+func (s *Stats) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return s.Snapshot()
+	}))
+}
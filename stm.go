@@ -0,0 +1,221 @@
+package linkedlist
+
+import (
+	"context"
+)
+
+// Tx is a transaction over node states, see Atomically. Transaction must not be
+// used outside of the function it has been passed to
+type Tx struct {
+	// reads keeps states observed by transaction, they are expected to be
+	// unchanged on commit
+	reads map[Node]*State
+
+	// writes keeps new states of nodes changed by transaction
+	writes map[Node]*State
+
+	// order keeps nodes in order they have been read in
+	order []Node
+
+	// events keeps inserts and deletes to report once transaction commits
+	events []txEvent
+}
+
+// txEvent is an insert or delete done by transaction
+type txEvent struct {
+	observer   Observer
+	pred, node Node
+	delete     bool
+}
+
+// Atomically runs fn in a transaction and commits all changes it has made at
+// once. If any node read by transaction has been changed concurrently fn is
+// run again, so fn must not have side effects besides changes made through the
+// transaction.
+//
+// Error returned by fn aborts transaction, no changes are applied and error is
+// returned to the caller. Transaction reads are validated on commit only, so fn
+// might observe inconsistent states of different nodes. Such run is never
+// committed, but fn should be ready to it, for example do not loop forever.
+//
+// Transactions are committed with MCAS and respect FREEZE/DELETE protocol, so
+// they could be mixed with Insert, Delete and other list functions. Inserts and
+// deletes made by Tx.Insert and Tx.Delete are reported to observers once
+// transaction commits, changes made by SetNext and SetFlags are not
+func Atomically(fn func(tx *Tx) error) error {
+	return AtomicallyCtx(context.Background(), nil, fn)
+}
+
+// AtomicallyCtx is Atomically which waits between attempts according to the
+// policy. Function gives up once context is done or policy reaches attempts
+// limit. Nil policy means DefaultBackoff
+func AtomicallyCtx(ctx context.Context, policy *Backoff, fn func(tx *Tx) error) error {
+	policy = policyOf(policy)
+	for failures := 0; ; failures++ {
+		if failures > 0 {
+			if err := policy.wait(ctx, failures); err != nil {
+				return err
+			}
+		}
+
+		tx := &Tx{reads: make(map[Node]*State), writes: make(map[Node]*State)}
+		if err := fn(tx); err != nil {
+			// Error might be caused by inconsistent reads, then run again
+			if tx.validate() {
+				return err
+			}
+			continue
+		}
+		if tx.commit() {
+			return nil
+		}
+	}
+}
+
+// load returns state of the node as it seen by transaction
+func (tx *Tx) load(node Node) *State {
+	if state, ok := tx.writes[node]; ok {
+		return state
+	}
+	if state, ok := tx.reads[node]; ok {
+		return state
+	}
+
+	// Delete in progress must be completed first, otherwise transaction
+	// could not change predecessor of the node being deleted
+	state := LoadState(node)
+	for state != nil && state.IsFreezed() {
+		help(nil, node, state.Next)
+		state = LoadState(node)
+	}
+
+	tx.reads[node] = state
+	tx.order = append(tx.order, node)
+	return state
+}
+
+// store sets state of the node, state is applied on commit
+func (tx *Tx) store(node Node, state *State) {
+	tx.load(node)
+	tx.writes[node] = state
+}
+
+// view returns copy of the node state seen by transaction
+func (tx *Tx) view(node Node) State {
+	if state := tx.load(node); state != nil {
+		return *state
+	}
+	return State{}
+}
+
+// Next returns the node linked after the given one. Unlike package level Next
+// removed nodes are not skipped
+func (tx *Tx) Next(node Node) Node {
+	return tx.view(node).Next
+}
+
+// Flags returns flags of the node
+func (tx *Tx) Flags(node Node) Flags {
+	return tx.view(node).Flags
+}
+
+// SetNext links next node after the given one
+func (tx *Tx) SetNext(node, next Node) {
+	state := tx.view(node)
+	state.Next = next
+	tx.store(node, &state)
+}
+
+// SetFlags changes flags of the node
+func (tx *Tx) SetFlags(node Node, flags Flags) {
+	state := tx.view(node)
+	state.Flags = flags
+	tx.store(node, &state)
+}
+
+// Insert links node right after the given one. Function returns false if node
+// after which insert is requested is removed
+func (tx *Tx) Insert(after, node Node) bool {
+	if tx.Flags(after)&DELETE != 0 {
+		return false
+	}
+
+	tx.store(node, &State{Next: tx.Next(after)})
+	tx.SetNext(after, node)
+	tx.events = append(tx.events, txEvent{observer: observerOf(after), pred: after, node: node})
+	return true
+}
+
+// Delete unlinks node which follows prev. Function returns false if node does
+// not follow prev, prev is removed or node is removed already. Deleted node
+// gets the same state as after Delete call
+func (tx *Tx) Delete(prev, node Node) bool {
+	// Removed prev is unlinked already or about to be, so node stays linked
+	// from its alive predecessor
+	if tx.Flags(prev)&DELETE != 0 || tx.Next(prev) != node || tx.Flags(node)&DELETE != 0 {
+		return false
+	}
+
+	next := tx.Next(node)
+	tx.store(node, &State{Next: next, Back: prev, Flags: DELETE})
+	tx.SetNext(prev, next)
+	tx.events = append(tx.events, txEvent{observer: observerOf(prev), pred: prev, node: node, delete: true})
+	return true
+}
+
+// validate reports whether all nodes read by transaction still have states it
+// has observed
+func (tx *Tx) validate() bool {
+	for _, node := range tx.order {
+		if LoadState(node) != tx.reads[node] {
+			return false
+		}
+	}
+	return true
+}
+
+// commit applies transaction changes and reports whether it has succeeded.
+// Nodes which were only read are compared by MCAS and keep their states
+func (tx *Tx) commit() bool {
+	if len(tx.writes) == 0 {
+		return tx.validate()
+	}
+
+	updates := make([]Update, 0, len(tx.order))
+	for _, node := range tx.order {
+		old := tx.reads[node]
+		state, ok := tx.writes[node]
+		if !ok {
+			state = old
+		}
+		updates = append(updates, Update{Node: node, Old: old, New: state})
+	}
+
+	// States to replace are read already, so tickets taken now are greater
+	// than tickets of updates transaction depends on, see Sequencer
+	tickets := make([]uint64, len(tx.events))
+	for i, e := range tx.events {
+		if seq := sequencerOf(e.observer); seq != nil {
+			tickets[i] = seq.Ticket()
+		}
+	}
+
+	committed := MCAS(updates...)
+	for i, e := range tx.events {
+		switch {
+		case !committed:
+			if seq := sequencerOf(e.observer); seq != nil {
+				seq.Cancel(tickets[i])
+			}
+		case e.delete:
+			// Deleted node is unlinked by the same update
+			notifyLogicalDelete(e.observer, tickets[i], e.node)
+			if e.observer != nil {
+				e.observer.OnUnlink(e.pred, e.node)
+			}
+		default:
+			notifyInsert(e.observer, tickets[i], e.pred, e.node)
+		}
+	}
+	return committed
+}
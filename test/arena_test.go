@@ -0,0 +1,143 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// arenaValues returns values of all nodes after head
+func arenaValues(a *Arena, head Ref) []uint64 {
+	var result []uint64
+	for cur := a.Next(head); cur != 0; cur = a.Next(cur) {
+		result = append(result, a.Value(cur))
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Arena tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestArenaInsertDelete verifies basic arena list operations
+func TestArenaInsertDelete(t *testing.T) {
+	assert := assert.New(t)
+	a := NewArena(8)
+	head, _ := a.Alloc(0)
+	n1, _ := a.Alloc(10)
+	n2, _ := a.Alloc(20)
+	n3, _ := a.Alloc(30)
+
+	a.Insert(head, n3)
+	a.Insert(head, n1)
+	left, ok := a.Insert(n1, n2)
+	assert.True(ok, "inserted")
+	assert.Equal(n1, left, "inserted after n1")
+	assert.Equal([]uint64{10, 20, 30}, arenaValues(a, head), "values")
+
+	left, removed, byme := a.Delete(head, n2)
+	assert.True(removed && byme, "deleted")
+	assert.Equal(n1, left, "predecessor")
+	assert.Equal([]uint64{10, 30}, arenaValues(a, head), "values")
+
+	next, back, flags := a.State(n2)
+	assert.Equal(n3, next, "n2.next")
+	assert.Equal(n1, back, "n2.back")
+	assert.Equal(DELETE, flags, "n2.flags")
+
+	_, removed, _ = a.Delete(head, n2)
+	assert.False(removed, "not found")
+
+	// Insert after removed node goes after its alive predecessor
+	n4, _ := a.Alloc(40)
+	left, _ = a.Insert(n2, n4)
+	assert.Equal(n1, left, "walked back")
+	assert.Equal([]uint64{10, 40, 30}, arenaValues(a, head), "values")
+}
+
+// TestArenaFull verifies arena capacity limit
+func TestArenaFull(t *testing.T) {
+	assert := assert.New(t)
+	a := NewArena(2)
+	a.Alloc(1)
+	a.Alloc(2)
+
+	_, err := a.Alloc(3)
+	assert.Equal(ErrArenaFull, err, "full")
+	assert.Equal(2, a.Len(), "len")
+}
+
+// TestArenaConcurrent verifies concurrent inserts and deletes in mapped arena
+func TestArenaConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	a, err := NewMappedArena(100001)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer a.Close()
+
+	head, _ := a.Alloc(0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 12500; i++ {
+				r, err := a.Alloc(uint64(g*12500 + i))
+				assert.NoError(err, "allocated")
+				a.Insert(head, r)
+				if i%2 == 0 {
+					_, _, byme := a.Delete(head, r)
+					assert.True(byme, "deleted by me")
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Len(arenaValues(a, head), 50000, "odd nodes left")
+	for cur := head; cur != 0; cur = a.Next(cur) {
+		_, _, flags := a.State(cur)
+		assert.Equal(NONE, flags, "alive node")
+	}
+}
+
+// TestArenaConsecutiveDeletes verifies that concurrent deletes of neighbour
+// nodes, which build chains of freezed nodes, remove all of them
+func TestArenaConsecutiveDeletes(t *testing.T) {
+	assert := assert.New(t)
+	const size = 1000
+
+	for round := 0; round < 20; round++ {
+		a := NewArena(size + 2)
+		head, _ := a.Alloc(0)
+		nodes := make([]Ref, size)
+		for i := range nodes {
+			nodes[i], _ = a.Alloc(uint64(i + 1))
+		}
+		last, _ := a.Alloc(size + 1)
+		a.Insert(head, last)
+		for i := size - 1; i >= 0; i-- {
+			a.Insert(head, nodes[i])
+		}
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				<-start
+				for i := g; i < size; i += 8 {
+					a.Delete(head, nodes[i])
+				}
+			}(g)
+		}
+		close(start)
+		wg.Wait()
+
+		assert.Equal([]uint64{size + 1}, arenaValues(a, head), "all deleted")
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Backoff tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestBackoffDelay verifies delay grows exponentially and stays in limits
+func TestBackoffDelay(t *testing.T) {
+	assert := assert.New(t)
+	b := Backoff{Base: time.Microsecond, Max: 100 * time.Microsecond}
+
+	assert.Zero(b.Delay(0), "no failures")
+	for failures := 1; failures < 100; failures++ {
+		limit := b.Max
+		if failures < 8 {
+			limit = time.Microsecond << (failures - 1)
+		}
+		for i := 0; i < 10; i++ {
+			delay := b.Delay(failures)
+			assert.True(delay >= 0 && delay <= limit, "delay %v after %d failures", delay, failures)
+		}
+	}
+
+	b = Backoff{}
+	assert.Zero(b.Delay(10), "no delay")
+}
+
+// TestInsertCtxAttempts verifies insert gives up once attempts limit reached
+func TestInsertCtxAttempts(t *testing.T) {
+	assert := assert.New(t)
+
+	// First attempt to insert after removed n2 fails
+	n1, n2, n3 := makelist(10, NONE, 20, DELETE, 30, NONE)
+	LoadState(n1).Next = n3
+	LoadState(n2).Back = n1
+
+	_, err := InsertCtx(context.Background(), n2, NewIntNode(25), &Backoff{MaxAttempts: 1})
+	assert.Equal(ErrTooManyAttempts, err, "gave up")
+	assert.Equal([]int{30}, values(n1), "not inserted")
+
+	left, err := InsertCtx(context.Background(), n2, NewIntNode(25), &Backoff{MaxAttempts: 2})
+	assert.NoError(err, "inserted")
+	assert.Equal(n1, left, "walked back")
+	assert.Equal([]int{25, 30}, values(n1), "inserted")
+}
+
+// TestDeleteCtxCanceled verifies operations do nothing with done context
+func TestDeleteCtxCanceled(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2, _ := makelist(10, NONE, 20, NONE, 30, NONE)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, _, err := DeleteCtx(ctx, n1, n2, nil)
+	assert.Equal(context.Canceled, err, "delete canceled")
+	_, err = InsertCtx(ctx, n1, NewIntNode(15), nil)
+	assert.Equal(context.Canceled, err, "insert canceled")
+	assert.Equal([]int{20, 30}, values(n1), "list intact")
+
+	_, removed, byme, err := DeleteCtx(context.Background(), n1, n2, nil)
+	assert.NoError(err, "deleted")
+	assert.True(removed && byme, "deleted by me")
+	assert.Equal([]int{30}, values(n1), "n2 removed")
+}
+
+// TestBackoffConcurrent runs contended inserts and deletes with backoff and
+// verifies every operation completes
+func TestBackoffConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	head := NewIntNode(-1)
+	policy := &Backoff{Base: time.Microsecond, Max: 50 * time.Microsecond}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				node := NewIntNode(g*1000 + i)
+				_, err := InsertCtx(context.Background(), head, node, policy)
+				assert.NoError(err, "inserted")
+				_, removed, byme, err := DeleteCtx(context.Background(), head, node, policy)
+				assert.NoError(err, "deleted")
+				assert.True(removed && byme, "deleted by me")
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Empty(values(head), "empty list")
+}
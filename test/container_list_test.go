@@ -0,0 +1,212 @@
+package test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+	list "github.com/xphoenix/linkedlist/list"
+)
+
+// listValues returns values of all elements of the list
+func listValues(l *list.List) []any {
+	var result []any
+	for e := l.Front(); e != nil; e = e.Next() {
+		result = append(result, e.Value)
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// container/list facade tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestConcurrentListAPI verifies container/list compatible operations
+func TestConcurrentListAPI(t *testing.T) {
+	assert := assert.New(t)
+
+	var l list.List
+	assert.Nil(l.Front(), "empty front")
+	assert.Nil(l.Back(), "empty back")
+
+	e2 := l.PushBack(2)
+	e1 := l.PushFront(1)
+	e4 := l.PushBack(4)
+	e3 := l.InsertBefore(3, e4)
+	e5 := l.InsertAfter(5, e4)
+	assert.Equal([]any{1, 2, 3, 4, 5}, listValues(&l), "values")
+	assert.Equal(5, l.Len(), "len")
+	assert.Equal(e1, l.Front(), "front")
+	assert.Equal(e5, l.Back(), "back")
+	assert.Equal(e3, e2.Next(), "next")
+
+	assert.Equal(3, l.Remove(e3), "removed value")
+	assert.Equal([]any{1, 2, 4, 5}, listValues(&l), "values")
+	assert.Equal(4, l.Len(), "len")
+	assert.Nil(e3.Next(), "next of removed")
+
+	// Removed element is not an anchor anymore
+	assert.Nil(l.InsertAfter(6, e3), "insert after removed")
+	assert.Nil(l.InsertBefore(6, e3), "insert before removed")
+	l.MoveToFront(e3)
+	assert.Equal([]any{1, 2, 4, 5}, listValues(&l), "removed not moved")
+
+	// Moved element stays valid
+	l.MoveToFront(e4)
+	assert.Equal([]any{4, 1, 2, 5}, listValues(&l), "values")
+	assert.Equal(e4, l.Front(), "front")
+	assert.Equal(e1, e4.Next(), "next of moved")
+	assert.Equal(5, l.InsertAfter(6, e2).Next().Value, "anchor after move")
+	assert.Equal(6, l.Remove(e2.Next()), "removed")
+	assert.Equal(4, l.Len(), "len")
+	l.MoveToFront(e5)
+	l.MoveToFront(e4)
+	assert.Equal([]any{4, 5, 1, 2}, listValues(&l), "values")
+	assert.Equal(e2, l.Back(), "back")
+
+	// Elements of other lists are ignored
+	other := list.New()
+	assert.Nil(other.InsertAfter(7, e1), "foreign mark")
+	other.Remove(e1)
+	other.MoveToFront(e1)
+	assert.Equal([]any{4, 5, 1, 2}, listValues(&l), "values")
+}
+
+// TestConcurrentListConcurrent runs pushes, moves and removes concurrently
+func TestConcurrentListConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	l := list.New()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				var e *list.Element
+				if i%2 == 0 {
+					e = l.PushFront(i)
+				} else {
+					e = l.PushBack(i)
+				}
+				if l.MoveToFront(e); i%3 == 0 {
+					assert.Equal(i, l.Remove(e), "removed")
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Len(listValues(l), l.Len(), "len")
+	assert.Equal(8*333, l.Len(), "len")
+}
+
+// TestConcurrentListLenNonNegative verifies that Len does not go below zero
+// when elements are removed right after they are linked
+func TestConcurrentListLenNonNegative(t *testing.T) {
+	assert := assert.New(t)
+	l := list.New()
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				l.PushBack(i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; {
+				if e := l.Front(); e != nil {
+					l.Remove(e)
+					i++
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-done:
+			assert.Len(listValues(l), l.Len(), "len")
+			return
+		default:
+			if n := l.Len(); n < 0 {
+				assert.Fail("negative len", "%d", n)
+				<-done
+				return
+			}
+		}
+	}
+}
+
+// TestConcurrentListMoveRemove moves and removes the same elements concurrently
+// and verifies removed elements never come back
+func TestConcurrentListMoveRemove(t *testing.T) {
+	assert := assert.New(t)
+	l := list.New()
+
+	elems := make([]*list.Element, 200)
+	for i := range elems {
+		elems[i] = l.PushBack(i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for r := 0; r < 20; r++ {
+				for i := g; i < len(elems); i += 4 {
+					l.MoveToFront(elems[i])
+				}
+			}
+		}(g)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(elems); i += 8 {
+				l.Remove(elems[i])
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	var expected []int
+	for i := range elems {
+		if i%8 >= 4 {
+			expected = append(expected, i)
+		}
+	}
+	var actual []int
+	for _, v := range listValues(l) {
+		actual = append(actual, v.(int))
+	}
+	sort.Ints(actual)
+	assert.Equal(expected, actual, "values")
+	assert.Equal(len(expected), l.Len(), "len")
+}
+
+// TestConcurrentListObserved verifies every push is reported as an insert
+func TestConcurrentListObserved(t *testing.T) {
+	assert := assert.New(t)
+	r, stats := newRecorder(), NewStats()
+	defer SetObserver(SetObserver(r))
+	defer SetStats(SetStats(stats))
+
+	l := list.New()
+	l.PushFront(1)
+	l.PushBack(2)
+	l.PushBack(3)
+	assert.Equal(3, l.Len(), "len")
+	assert.Len(r.inserted, 3, "observed inserts")
+	assert.Equal(uint64(3), stats.Snapshot().Inserts, "counted inserts")
+}
@@ -0,0 +1,227 @@
+package test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xphoenix/linkedlist/durable"
+)
+
+// openDurable opens durable list of strings at path
+func openDurable(t *testing.T, path string, opts durable.Options) *durable.List[string] {
+	l, err := durable.Open(path, durable.JSONCodec[string](), opts)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	return l
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Durable list tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestDurableReplay verifies list is restored from the log
+func TestDurableReplay(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "list.wal")
+
+	l := openDurable(t, path, durable.Options{})
+	c, _ := l.PushFront("c")
+	a, _ := l.PushFront("a")
+	b, _ := l.InsertAfter("b", a)
+	removed, err := l.Remove(a)
+	assert.True(removed, "removed")
+	assert.NoError(err, "remove logged")
+
+	// Insert after deleted element goes after its alive predecessor
+	_, err = l.InsertAfter("x", a)
+	assert.NoError(err, "insert logged")
+	l.InsertAfter("d", c)
+	l.Remove(b)
+	assert.Equal([]string{"x", "c", "d"}, l.Values(), "values")
+	assert.NoError(l.Close(), "closed")
+
+	restored := openDurable(t, path, durable.Options{})
+	defer restored.Close()
+	assert.Equal([]string{"x", "c", "d"}, restored.Values(), "restored")
+
+	// Restored list keeps working and logging
+	restored.PushFront("w")
+	restored.Remove(restored.Next(restored.Front()))
+	assert.Equal([]string{"w", "c", "d"}, restored.Values(), "changed")
+}
+
+// TestDurableCompaction verifies list is restored from snapshot and log
+func TestDurableCompaction(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "list.wal")
+
+	l := openDurable(t, path, durable.Options{CompactEvery: 10})
+	for _, v := range []string{"e", "d", "c", "b", "a"} {
+		l.PushFront(v)
+	}
+	for i := 0; i < 3; i++ {
+		l.Remove(l.Front())
+		e, _ := l.PushFront("z")
+		l.InsertAfter("y", e)
+		l.Remove(e)
+	}
+	expected := l.Values()
+	assert.NoError(l.Close(), "closed")
+
+	_, err := os.Stat(path + ".snapshot")
+	assert.NoError(err, "snapshot written")
+
+	restored := openDurable(t, path, durable.Options{})
+	defer restored.Close()
+	assert.Equal(expected, restored.Values(), "restored")
+}
+
+// TestDurableTornWrite verifies broken tail of the log is dropped
+func TestDurableTornWrite(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "list.wal")
+
+	l := openDurable(t, path, durable.Options{})
+	l.PushFront("b")
+	l.PushFront("a")
+	assert.NoError(l.Close(), "closed")
+
+	// Cut the last record in the middle
+	info, _ := os.Stat(path)
+	assert.NoError(os.Truncate(path, info.Size()-3), "truncated")
+
+	restored := openDurable(t, path, durable.Options{})
+	assert.Equal([]string{"b"}, restored.Values(), "last record dropped")
+	restored.PushFront("c")
+	assert.NoError(restored.Close(), "closed")
+
+	restored = openDurable(t, path, durable.Options{})
+	defer restored.Close()
+	assert.Equal([]string{"c", "b"}, restored.Values(), "log appended after cut")
+}
+
+// TestDurableCorruptedRecord verifies broken record in the middle of the log
+// is not mistaken for a torn write
+func TestDurableCorruptedRecord(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "list.wal")
+
+	l := openDurable(t, path, durable.Options{})
+	l.PushFront("b")
+	l.PushFront("a")
+	assert.NoError(l.Close(), "closed")
+
+	// Flip the last byte of the first record body
+	data, err := os.ReadFile(path)
+	assert.NoError(err, "read")
+	first := 8 + int(binary.LittleEndian.Uint32(data))
+	data[first-1] ^= 0xff
+	assert.NoError(os.WriteFile(path, data, 0o644), "written")
+
+	_, err = durable.Open(path, durable.JSONCodec[string](), durable.Options{})
+	assert.ErrorIs(err, durable.ErrCorrupted, "corrupted log rejected")
+
+	// The same damage in the last record is a torn write
+	data[first-1] ^= 0xff
+	data[len(data)-1] ^= 0xff
+	assert.NoError(os.WriteFile(path, data, 0o644), "written")
+
+	restored := openDurable(t, path, durable.Options{})
+	defer restored.Close()
+	assert.Equal([]string{"b"}, restored.Values(), "last record dropped")
+}
+
+// TestDurableZeroTail verifies zeros appended by file system on crash are
+// dropped as a torn write
+func TestDurableZeroTail(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "list.wal")
+
+	l := openDurable(t, path, durable.Options{})
+	l.PushFront("a")
+	assert.NoError(l.Close(), "closed")
+
+	info, _ := os.Stat(path)
+	assert.NoError(os.Truncate(path, info.Size()+64), "extended")
+
+	restored := openDurable(t, path, durable.Options{})
+	defer restored.Close()
+	assert.Equal([]string{"a"}, restored.Values(), "zeros dropped")
+}
+
+// TestDurableConcurrent verifies concurrent writers are logged in order
+func TestDurableConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "list.wal")
+
+	l := openDurable(t, path, durable.Options{CompactEvery: 100})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				e, err := l.PushFront("v")
+				assert.NoError(err, "pushed")
+				if i%3 == 0 {
+					l.Remove(e)
+				}
+				l.InsertAfter("w", e)
+			}
+		}()
+	}
+	wg.Wait()
+	expected := l.Values()
+	assert.NoError(l.Close(), "closed")
+
+	restored := openDurable(t, path, durable.Options{})
+	defer restored.Close()
+	assert.Equal(expected, restored.Values(), "restored")
+}
+
+// TestDurableCloseConcurrent verifies writers racing with Close either fail
+// before logging their change or succeed with the change on disk
+func TestDurableCloseConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "list.wal")
+
+	l := openDurable(t, path, durable.Options{})
+	var mu sync.Mutex
+	pushed := map[string]bool{}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				v := fmt.Sprintf("%d-%d", g, i)
+				if _, err := l.PushFront(v); err != nil {
+					assert.ErrorIs(err, durable.ErrClosed, "closed")
+					return
+				}
+				mu.Lock()
+				pushed[v] = true
+				mu.Unlock()
+			}
+		}(g)
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(l.Close(), "closed")
+	wg.Wait()
+
+	restored := openDurable(t, path, durable.Options{})
+	defer restored.Close()
+	values := restored.Values()
+	assert.Len(values, len(pushed), "every logged change reported")
+	for _, v := range values {
+		assert.True(pushed[v], "%s reported", v)
+	}
+}
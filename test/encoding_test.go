@@ -0,0 +1,63 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// intCodec encodes IntNode with its binary marshaler
+var intCodec = BinaryCodec(func() *IntNode { return NewIntNode(0) })
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Encoding tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestEncodeDecode verifies list survives round trip
+func TestEncodeDecode(t *testing.T) {
+	assert := assert.New(t)
+	head := NewIntNode(-1)
+	for i := 100; i > 0; i-- {
+		Insert(head, NewIntNode(i*1000))
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(Encode(&buf, head, intCodec), "encoded")
+
+	restored := NewIntNode(-1)
+	n, err := Decode(&buf, restored, intCodec)
+	assert.NoError(err, "decoded")
+	assert.Equal(100, n, "count")
+	assert.Equal(values(head), values(restored), "same values")
+}
+
+// TestEncodeSkipsDeleted verifies only alive nodes are written
+func TestEncodeSkipsDeleted(t *testing.T) {
+	assert := assert.New(t)
+	head, _, _ := makelist(10, NONE, 20, FREEZE, 30, NONE)
+
+	var buf bytes.Buffer
+	assert.NoError(Encode(&buf, head, intCodec), "encoded")
+
+	restored := NewIntNode(-1)
+	n, err := Decode(&buf, restored, intCodec)
+	assert.NoError(err, "decoded")
+	assert.Equal(1, n, "count")
+	assert.Equal([]int{20}, values(restored), "alive values")
+}
+
+// TestDecodeInvalid verifies corrupted input is rejected
+func TestDecodeInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Decode(bytes.NewReader([]byte("JUNK\x01\x00")), NewIntNode(-1), intCodec)
+	assert.Equal(ErrInvalidFormat, err, "bad magic")
+
+	_, err = Decode(bytes.NewReader([]byte("LLST\x07\x00")), NewIntNode(-1), intCodec)
+	assert.Equal(ErrUnsupportedVersion, err, "bad version")
+
+	_, err = Decode(bytes.NewReader([]byte("LLST\x01\x05\x02")), NewIntNode(-1), intCodec)
+	assert.Equal(ErrInvalidFormat, err, "truncated record")
+}
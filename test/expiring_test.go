@@ -0,0 +1,159 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// TTLNode is an int node with deadline
+type TTLNode struct {
+	*IntNode
+	deadline time.Time
+}
+
+// Deadline implements ExpiringNode interface
+func (n *TTLNode) Deadline() time.Time {
+	return n.deadline
+}
+
+// ttlValues returns values of all nodes after head
+func ttlValues(head Node) []int {
+	var result []int
+	for cur := Next(head); cur != nil; cur = Next(cur) {
+		result = append(result, cur.(*TTLNode).value)
+	}
+	return result
+}
+
+// expiringValues returns values of alive nodes of the list
+func expiringValues(l *ExpiringList) []int {
+	var result []int
+	for cur := l.Next(l.Head()); cur != nil; cur = l.Next(cur) {
+		result = append(result, cur.(*TTLNode).value)
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Expiring list tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestExpiringListSweep verifies sweeper deletes expired nodes and reports
+// each of them once
+func TestExpiringListSweep(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+
+	var expired []int
+	l := NewExpiringList(clock, func(n ExpiringNode) { expired = append(expired, n.(*TTLNode).value) })
+	for i := 5; i > 0; i-- {
+		l.Insert(&TTLNode{IntNode: NewIntNode(i), deadline: clock.Now().Add(time.Duration(i) * time.Second)})
+	}
+
+	assert.Zero(l.Sweep(), "nothing expired")
+	clock.Advance(2 * time.Second)
+	assert.Equal(2, l.Sweep(), "two expired")
+	assert.Zero(l.Sweep(), "expired once")
+	assert.Equal([]int{1, 2}, expired, "callback")
+	assert.Equal([]int{3, 4, 5}, ttlValues(l.Head()), "list")
+}
+
+// TestExpiringListLazy verifies Next skips and deletes expired nodes
+func TestExpiringListLazy(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+
+	var expired []int
+	l := NewExpiringList(clock, func(n ExpiringNode) { expired = append(expired, n.(*TTLNode).value) })
+	for i := 5; i > 0; i-- {
+		deadline := clock.Now().Add(time.Hour)
+		if i%2 == 0 {
+			deadline = clock.Now().Add(time.Second)
+		}
+		l.Insert(&TTLNode{IntNode: NewIntNode(i), deadline: deadline})
+	}
+
+	assert.Equal([]int{1, 2, 3, 4, 5}, expiringValues(l), "before expiry")
+	clock.Advance(time.Second)
+	assert.Equal([]int{1, 3, 5}, expiringValues(l), "after expiry")
+	assert.Equal([]int{2, 4}, expired, "callback")
+	assert.Equal([]int{1, 3, 5}, ttlValues(l.Head()), "deleted")
+}
+
+// TestExpiringListConcurrent runs sweepers, readers and deletes concurrently
+// and verifies every node is reported at most once and only if it has not been
+// deleted explicitly
+func TestExpiringListConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+
+	const count = 2000
+	var reported [count]int32
+	l := NewExpiringList(clock, func(n ExpiringNode) { atomic.AddInt32(&reported[n.(*TTLNode).value], 1) })
+
+	nodes := make([]*TTLNode, count)
+	for i := range nodes {
+		nodes[i] = &TTLNode{IntNode: NewIntNode(i), deadline: clock.Now().Add(time.Duration(i%10) * time.Second)}
+		l.Insert(nodes[i])
+	}
+
+	var deleted [count]bool
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			clock.Advance(time.Second)
+			l.Sweep()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			expiringValues(l)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; i += 3 {
+			_, deleted[i] = l.Delete(nodes[i])
+		}
+	}()
+	wg.Wait()
+	l.Sweep()
+
+	assert.Empty(ttlValues(l.Head()), "all expired")
+	for i := range nodes {
+		if deleted[i] {
+			assert.Zero(reported[i], "deleted node %d reported", i)
+		} else {
+			assert.Equal(int32(1), reported[i], "node %d reported once", i)
+		}
+	}
+}
+
+// TestExpiringListRun verifies ticker driven sweeper
+func TestExpiringListRun(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+
+	done := make(chan ExpiringNode, 1)
+	l := NewExpiringList(clock, func(n ExpiringNode) { done <- n })
+	node := &TTLNode{IntNode: NewIntNode(1), deadline: clock.Now().Add(time.Second)}
+	l.Insert(node)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() { result <- l.Run(ctx, time.Second) }()
+
+	clock.ticks <- clock.Advance(time.Second)
+	assert.Equal(ExpiringNode(node), <-done, "expired")
+	cancel()
+	assert.Equal(context.Canceled, <-result, "stopped")
+}
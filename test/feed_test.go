@@ -0,0 +1,198 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// newFeedList creates empty list which reports events into a new feed
+func newFeedList(history int) (*Feed, func(v int) Node) {
+	feed := NewFeed(history)
+	return feed, func(v int) Node { return &ObservedIntNode{NewIntNode(v), feed} }
+}
+
+// stallingFeed is a feed which stops goroutine reporting insert until resumed,
+// as if it has been preempted right after its state update
+type stallingFeed struct {
+	*Feed
+	inserted chan struct{}
+	resume   chan struct{}
+}
+
+// OnInsertAt implements Sequencer interface
+func (f *stallingFeed) OnInsertAt(ticket uint64, pred, node Node) {
+	close(f.inserted)
+	<-f.resume
+	f.Feed.OnInsertAt(ticket, pred, node)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Feed tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestFeedEvents verifies events are delivered in order with sequence numbers
+func TestFeedEvents(t *testing.T) {
+	assert := assert.New(t)
+	feed, newNode := newFeedList(16)
+	sub := feed.Subscribe(0, 16)
+	defer sub.Close()
+
+	head, n1, n2 := newNode(-1), newNode(10), newNode(20)
+	Insert(head, n1)
+	Insert(n1, n2)
+	Delete(head, n1)
+
+	e := <-sub.C
+	assert.Equal(Event{Seq: 1, Kind: EventInsert, Pred: head, Node: n1}, e, "insert n1")
+	e = <-sub.C
+	assert.Equal(Event{Seq: 2, Kind: EventInsert, Pred: n1, Node: n2}, e, "insert n2")
+	e = <-sub.C
+	assert.Equal(Event{Seq: 3, Kind: EventDelete, Node: n1}, e, "delete n1")
+	assert.Equal(uint64(3), feed.Seq(), "feed seq")
+}
+
+// TestFeedSlowSubscriber verifies subscriber is notified about missed events
+func TestFeedSlowSubscriber(t *testing.T) {
+	assert := assert.New(t)
+	feed, newNode := newFeedList(16)
+	sub := feed.Subscribe(0, 2)
+	defer sub.Close()
+
+	head := newNode(-1)
+	for i := 0; i < 5; i++ {
+		Insert(head, newNode(i))
+	}
+
+	// First two events fit the buffer, other 3 are lost
+	assert.Equal(uint64(1), (<-sub.C).Seq, "first event")
+	assert.Equal(uint64(2), (<-sub.C).Seq, "second event")
+
+	// Lag is reported before the next event
+	Insert(head, newNode(5))
+	assert.Equal(Event{Seq: 5, Kind: EventLag, Missed: 3}, <-sub.C, "lag reported")
+
+	e := <-sub.C
+	assert.Equal(uint64(6), e.Seq, "continue with latest")
+	assert.Equal(EventInsert, e.Kind, "insert after lag")
+}
+
+// TestFeedResume verifies subscription replays events from the history
+func TestFeedResume(t *testing.T) {
+	assert := assert.New(t)
+	feed, newNode := newFeedList(4)
+
+	head := newNode(-1)
+	for i := 0; i < 6; i++ {
+		Insert(head, newNode(i))
+	}
+
+	// Events 3..6 are in the history
+	sub := feed.Subscribe(4, 8)
+	defer sub.Close()
+	for seq := uint64(4); seq <= 6; seq++ {
+		assert.Equal(seq, (<-sub.C).Seq, "replayed")
+	}
+
+	// Events 1 and 2 are gone
+	old := feed.Subscribe(1, 8)
+	defer old.Close()
+	assert.Equal(Event{Seq: 2, Kind: EventLag, Missed: 2}, <-old.C, "lag reported")
+	for seq := uint64(3); seq <= 6; seq++ {
+		assert.Equal(seq, (<-old.C).Seq, "replayed")
+	}
+
+	// Live events follow replay
+	Insert(head, newNode(6))
+	assert.Equal(uint64(7), (<-sub.C).Seq, "live event")
+	assert.Equal(uint64(7), (<-old.C).Seq, "live event")
+}
+
+// TestFeedLinearizationOrder verifies delete of a node is published after its
+// insert even if inserting goroutine reports late
+func TestFeedLinearizationOrder(t *testing.T) {
+	assert := assert.New(t)
+	feed := &stallingFeed{NewFeed(16), make(chan struct{}), make(chan struct{})}
+	sub := feed.Subscribe(0, 16)
+	defer sub.Close()
+
+	head, node := &ObservedIntNode{NewIntNode(-1), feed}, &ObservedIntNode{NewIntNode(10), feed}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Insert(head, node)
+	}()
+
+	// Delete completes while insert is not reported yet
+	<-feed.inserted
+	_, removed, byme := Delete(head, node)
+	assert.True(removed && byme, "deleted")
+	select {
+	case e := <-sub.C:
+		assert.Fail("delete published before insert", "%v", e)
+	default:
+	}
+
+	close(feed.resume)
+	<-done
+	assert.Equal(Event{Seq: 1, Kind: EventInsert, Pred: head, Node: node}, <-sub.C, "insert")
+	assert.Equal(Event{Seq: 2, Kind: EventDelete, Node: node}, <-sub.C, "delete")
+}
+
+// TestFeedConcurrentOrder verifies every node is published inserted before it
+// is published deleted under concurrent inserts and deletes of the same nodes
+func TestFeedConcurrentOrder(t *testing.T) {
+	const (
+		workers = 4
+		count   = 500
+	)
+
+	assert := assert.New(t)
+	feed, newNode := newFeedList(16)
+	sub := feed.Subscribe(0, 2*workers*count)
+	defer sub.Close()
+
+	head := newNode(-1)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		nodes := make(chan Node, count)
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			defer close(nodes)
+			for i := 0; i < count; i++ {
+				node := newNode(w*count + i)
+				nodes <- node
+				Insert(head, node)
+			}
+		}(w)
+
+		// Deleter races with the inserter for every node
+		go func() {
+			defer wg.Done()
+			for node := range nodes {
+				for {
+					if _, removed, _ := Delete(head, node); removed {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	inserted := make(map[Node]bool)
+	for i := uint64(1); i <= 2*workers*count; i++ {
+		e := <-sub.C
+		assert.Equal(i, e.Seq, "sequence")
+		switch e.Kind {
+		case EventInsert:
+			inserted[e.Node] = true
+		case EventDelete:
+			assert.True(inserted[e.Node], "delete after insert")
+		}
+	}
+	assert.Equal(uint64(2*workers*count), feed.Seq(), "feed seq")
+}
@@ -1,6 +1,9 @@
 package test
 
 import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/xphoenix/linkedlist"
@@ -30,3 +33,32 @@ func (n *IntNode) String() string {
 func (n *IntNode) State() **linkedlist.State {
 	return &n.state
 }
+
+// MarshalJSON encodes node as its value
+func (n *IntNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.value)
+}
+
+// MarshalBinary encodes node's value as varint
+func (n *IntNode) MarshalBinary() ([]byte, error) {
+	return binary.AppendVarint(nil, int64(n.value)), nil
+}
+
+// UnmarshalBinary decodes node's value from varint
+func (n *IntNode) UnmarshalBinary(data []byte) error {
+	v, size := binary.Varint(data)
+	if size != len(data) {
+		return errors.New("invalid int node")
+	}
+	n.value = int(v)
+	return nil
+}
+
+// values returns values of all nodes after head
+func values(head linkedlist.Node) []int {
+	var result []int
+	for cur := linkedlist.Next(head); cur != nil; cur = linkedlist.Next(cur) {
+		result = append(result, cur.(*IntNode).value)
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// LinkedInt is a node embedding Link
+type LinkedInt struct {
+	Link
+	value int
+}
+
+// linkedValues returns values of all nodes after head
+func linkedValues(head *LinkedInt) []int {
+	var result []int
+	Range(head, func(n *LinkedInt) bool {
+		result = append(result, n.value)
+		return true
+	})
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Link tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestLinkZeroValue verifies that zero nodes could be used with generic
+// functions and with package level ones after that
+func TestLinkZeroValue(t *testing.T) {
+	assert := assert.New(t)
+	head, n1, n2, n3 := &LinkedInt{}, &LinkedInt{value: 1}, &LinkedInt{value: 2}, &LinkedInt{value: 3}
+
+	assert.Nil(NextOf(head), "empty list")
+	InsertOf(head, n3)
+	InsertOf(head, n1)
+	left, inserted := InsertOf(n1, n2)
+	assert.True(inserted, "inserted")
+	assert.Equal(n1, left, "inserted after n1")
+	assert.Equal([]int{1, 2, 3}, linkedValues(head), "values")
+
+	// Node interface keeps working
+	assert.Equal(Node(n1), Next(head), "next")
+	assert.Equal(n2, NextOf(n1), "next of")
+
+	left, removed, byme := DeleteOf(head, n2)
+	assert.True(removed && byme, "deleted")
+	assert.Equal(n1, left, "predecessor")
+	assert.True(LoadState(n2).IsRemoved(), "n2 removed")
+	assert.Equal([]int{1, 3}, linkedValues(head), "values")
+
+	_, removed, _ = DeleteOf(head, n2)
+	assert.False(removed, "not found")
+
+	// Range stops once callback returns false
+	var seen []int
+	Range(head, func(n *LinkedInt) bool {
+		seen = append(seen, n.value)
+		return false
+	})
+	assert.Equal([]int{1}, seen, "stopped")
+}
+
+// TestLinkNotFirst verifies that LinkOf rejects types where Link is not the
+// first field
+func TestLinkNotFirst(t *testing.T) {
+	type misplaced struct {
+		value int
+		Link
+	}
+
+	assert.Panics(t, func() { LinkOf(&misplaced{}) }, "misplaced link")
+	assert.NotPanics(t, func() { LinkOf(&LinkedInt{}) }, "first field")
+}
+
+// TestLinkMixedTypes verifies that generic functions reject nodes of other
+// types embedding Link
+func TestLinkMixedTypes(t *testing.T) {
+	type other struct {
+		Link
+	}
+
+	head, node := &LinkedInt{}, &other{}
+	LinkOf(head)
+	LinkOf(node)
+	Insert(head, node)
+
+	assert.Panics(t, func() { NextOf(head) }, "next")
+	assert.Panics(t, func() { Range(head, func(*LinkedInt) bool { return true }) }, "range")
+	assert.Panics(t, func() { DeleteOf(head, &LinkedInt{}) }, "delete")
+}
+
+// TestLinkConcurrent runs concurrent inserts and deletes of linked nodes
+func TestLinkConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	head := &LinkedInt{}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				n := &LinkedInt{value: g*1000 + i}
+				InsertOf(head, n)
+				if i%2 == 0 {
+					_, _, byme := DeleteOf(head, n)
+					assert.True(byme, "deleted by me")
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Len(linkedValues(head), 4000, "odd nodes left")
+}
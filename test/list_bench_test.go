@@ -0,0 +1,340 @@
+package test
+
+import (
+	"container/list"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/xphoenix/linkedlist"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Benchmarks
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// benchList is a minimal list abstraction used to run the same workload over
+// the lock-free list and over container/list protected by locks
+type benchList interface {
+	// InsertAfter adds a new value after anchor number i and returns handle of it
+	InsertAfter(i int) interface{}
+	// Delete removes value by the handle returned from InsertAfter
+	Delete(h interface{})
+	// Walk travels over the whole list and returns number of visited values
+	Walk() int
+}
+
+// lockFreeList adapts linkedlist package functions to benchList
+type lockFreeList struct {
+	head    *IntNode
+	anchors []*IntNode
+}
+
+func newLockFreeList(length int) benchList {
+	l := &lockFreeList{head: NewIntNode(-1), anchors: make([]*IntNode, length)}
+	for i := length - 1; i >= 0; i-- {
+		l.anchors[i] = NewIntNode(i)
+		linkedlist.Insert(l.head, l.anchors[i])
+	}
+	return l
+}
+
+func (l *lockFreeList) InsertAfter(i int) interface{} {
+	node := NewIntNode(i)
+	linkedlist.Insert(l.anchors[i], node)
+	return node
+}
+
+func (l *lockFreeList) Delete(h interface{}) {
+	linkedlist.Delete(l.head, h.(linkedlist.Node))
+}
+
+func (l *lockFreeList) Walk() int {
+	count := 0
+	for cur := linkedlist.Next(l.head); cur != nil; cur = linkedlist.Next(cur) {
+		count++
+	}
+	return count
+}
+
+// linkList is a lock-free list of nodes embedding Link
+type linkList struct {
+	head    *LinkedInt
+	anchors []*LinkedInt
+}
+
+func newLinkList(length int) benchList {
+	l := &linkList{head: &LinkedInt{value: -1}, anchors: make([]*LinkedInt, length)}
+	for i := length - 1; i >= 0; i-- {
+		l.anchors[i] = &LinkedInt{value: i}
+		linkedlist.InsertOf(l.head, l.anchors[i])
+	}
+	return l
+}
+
+func (l *linkList) InsertAfter(i int) interface{} {
+	node := &LinkedInt{value: i}
+	linkedlist.InsertOf(l.anchors[i], node)
+	return node
+}
+
+func (l *linkList) Delete(h interface{}) {
+	linkedlist.DeleteOf(l.head, h.(*LinkedInt))
+}
+
+func (l *linkList) Walk() int {
+	count := 0
+	linkedlist.Range(l.head, func(*LinkedInt) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// pooledList is lockFreeList which runs operations inside pool guards, so
+// states and deleted nodes are recycled
+type pooledList struct {
+	lockFreeList
+	pool  *linkedlist.Pool
+	nodes sync.Pool
+	reuse func(linkedlist.Node)
+}
+
+func newPooledList(length int) benchList {
+	l := &pooledList{lockFreeList: *newLockFreeList(length).(*lockFreeList), pool: linkedlist.NewPool()}
+	l.nodes.New = func() interface{} { return NewIntNode(0) }
+	l.reuse = func(n linkedlist.Node) { l.nodes.Put(n) }
+	return l
+}
+
+func (l *pooledList) InsertAfter(i int) interface{} {
+	node := l.nodes.Get().(*IntNode)
+	node.value = i
+
+	g := l.pool.Enter()
+	g.Insert(l.anchors[i], node)
+	g.Exit()
+	return node
+}
+
+func (l *pooledList) Delete(h interface{}) {
+	g := l.pool.Enter()
+	if _, _, byme := g.Delete(l.head, h.(linkedlist.Node)); byme {
+		g.RetireNode(h.(linkedlist.Node), l.reuse)
+	}
+	g.Exit()
+}
+
+func (l *pooledList) Walk() int {
+	g := l.pool.Enter()
+	count := 0
+	for cur := g.Next(l.head); cur != nil; cur = g.Next(cur) {
+		count++
+	}
+	g.Exit()
+	return count
+}
+
+// mutexList is a container/list guarded by sync.Mutex
+type mutexList struct {
+	mu      sync.Mutex
+	list    *list.List
+	anchors []*list.Element
+}
+
+func newMutexList(length int) benchList {
+	l := &mutexList{list: list.New(), anchors: make([]*list.Element, length)}
+	for i := 0; i < length; i++ {
+		l.anchors[i] = l.list.PushBack(i)
+	}
+	return l
+}
+
+func (l *mutexList) InsertAfter(i int) interface{} {
+	l.mu.Lock()
+	e := l.list.InsertAfter(i, l.anchors[i])
+	l.mu.Unlock()
+	return e
+}
+
+func (l *mutexList) Delete(h interface{}) {
+	l.mu.Lock()
+	l.list.Remove(h.(*list.Element))
+	l.mu.Unlock()
+}
+
+func (l *mutexList) Walk() int {
+	l.mu.Lock()
+	count := 0
+	for e := l.list.Front(); e != nil; e = e.Next() {
+		count++
+	}
+	l.mu.Unlock()
+	return count
+}
+
+// rwMutexList is a container/list guarded by sync.RWMutex, so walks do not
+// block each other
+type rwMutexList struct {
+	mu      sync.RWMutex
+	list    *list.List
+	anchors []*list.Element
+}
+
+func newRWMutexList(length int) benchList {
+	l := &rwMutexList{list: list.New(), anchors: make([]*list.Element, length)}
+	for i := 0; i < length; i++ {
+		l.anchors[i] = l.list.PushBack(i)
+	}
+	return l
+}
+
+func (l *rwMutexList) InsertAfter(i int) interface{} {
+	l.mu.Lock()
+	e := l.list.InsertAfter(i, l.anchors[i])
+	l.mu.Unlock()
+	return e
+}
+
+func (l *rwMutexList) Delete(h interface{}) {
+	l.mu.Lock()
+	l.list.Remove(h.(*list.Element))
+	l.mu.Unlock()
+}
+
+func (l *rwMutexList) Walk() int {
+	l.mu.RLock()
+	count := 0
+	for e := l.list.Front(); e != nil; e = e.Next() {
+		count++
+	}
+	l.mu.RUnlock()
+	return count
+}
+
+var (
+	benchImpls = []struct {
+		name string
+		make func(length int) benchList
+	}{
+		{"lockfree", newLockFreeList},
+		{"pooled", newPooledList},
+		{"link", newLinkList},
+		{"mutex", newMutexList},
+		{"rwmutex", newRWMutexList},
+	}
+	benchLengths    = []int{16, 1024, 16384}
+	benchGoroutines = []int{1, 4, 16}
+)
+
+// runConcurrent splits b.N iterations between given number of goroutines, the
+// first b.N%goroutines workers do one iteration more. Each goroutine gets own
+// random source, so workers do not contend on it
+func runConcurrent(b *testing.B, goroutines int, work func(r *rand.Rand)) {
+	var wg sync.WaitGroup
+	per, rest := b.N/goroutines, b.N%goroutines
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for g := 0; g < goroutines; g++ {
+		n := per
+		if g < rest {
+			n++
+		}
+
+		wg.Add(1)
+		go func(seed int64, n int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < n; i++ {
+				work(r)
+			}
+		}(int64(g), n)
+	}
+	wg.Wait()
+}
+
+// benchMatrix runs workload for every implementation, list length and number
+// of goroutines
+func benchMatrix(b *testing.B, work func(b *testing.B, l benchList, length, goroutines int)) {
+	for _, impl := range benchImpls {
+		for _, length := range benchLengths {
+			for _, goroutines := range benchGoroutines {
+				name := fmt.Sprintf("%s/len=%d/goroutines=%d", impl.name, length, goroutines)
+				b.Run(name, func(b *testing.B) {
+					work(b, impl.make(length), length, goroutines)
+				})
+			}
+		}
+	}
+}
+
+// BenchmarkInsert measures insertion after random node in the list. List grows
+// during the benchmark, so keep length in mind comparing results
+func BenchmarkInsert(b *testing.B) {
+	benchMatrix(b, func(b *testing.B, l benchList, length, goroutines int) {
+		runConcurrent(b, goroutines, func(r *rand.Rand) {
+			l.InsertAfter(r.Intn(length))
+		})
+	})
+}
+
+// BenchmarkDelete measures deletition of a node from random position. Each
+// iteration inserts a node and deletes it, so list length stays the same. Note
+// that lock-free Delete has to find predecessor while container/list has it
+func BenchmarkDelete(b *testing.B) {
+	benchMatrix(b, func(b *testing.B, l benchList, length, goroutines int) {
+		runConcurrent(b, goroutines, func(r *rand.Rand) {
+			l.Delete(l.InsertAfter(r.Intn(length)))
+		})
+	})
+}
+
+// BenchmarkNext measures full list traversal. Operation is a walk over the
+// whole list, so ns/op is time per walk and ns/node is time per node
+func BenchmarkNext(b *testing.B) {
+	benchMatrix(b, func(b *testing.B, l benchList, length, goroutines int) {
+		runConcurrent(b, goroutines, func(r *rand.Rand) {
+			l.Walk()
+		})
+		b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/float64(length), "ns/node")
+	})
+}
+
+// BenchmarkMixed runs reads and writes in different ratios. Read is a full
+// traversal, write is insert followed by delete of the inserted node
+func BenchmarkMixed(b *testing.B) {
+	for _, writes := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("writes=%d%%", writes), func(b *testing.B) {
+			benchMatrix(b, func(b *testing.B, l benchList, length, goroutines int) {
+				runConcurrent(b, goroutines, func(r *rand.Rand) {
+					if r.Intn(100) < writes {
+						l.Delete(l.InsertAfter(r.Intn(length)))
+					} else {
+						l.Walk()
+					}
+				})
+			})
+		})
+	}
+}
+
+// BenchmarkFanIn measures concurrent inserts at the front of a single list and
+// of a sharded list
+func BenchmarkFanIn(b *testing.B) {
+	for _, goroutines := range benchGoroutines {
+		b.Run(fmt.Sprintf("single/goroutines=%d", goroutines), func(b *testing.B) {
+			head := NewIntNode(-1)
+			runConcurrent(b, goroutines, func(r *rand.Rand) {
+				linkedlist.Insert(head, NewIntNode(0))
+			})
+		})
+		b.Run(fmt.Sprintf("sharded/goroutines=%d", goroutines), func(b *testing.B) {
+			l := linkedlist.NewShardedList(0)
+			runConcurrent(b, goroutines, func(r *rand.Rand) {
+				l.Insert(NewIntNode(0))
+			})
+		})
+	}
+}
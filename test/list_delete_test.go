@@ -1,6 +1,8 @@
 package test
 
 import (
+	"runtime/debug"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -92,6 +94,19 @@ func TestDeleteRemoved(t *testing.T) {
 	assert.Equal(NONE, state.Flags, "n3.flags")
 }
 
+// TestDeleteFromLast verifies deletition of the node which is not in the list
+// from the last node
+func TestDeleteFromLast(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2 := NewIntNode(10), NewIntNode(20)
+
+	del, result, byme := Delete(n1, n2)
+	assert.False(result, "deleted")
+	assert.False(byme, "deleted by thread")
+	assert.Nil(del, "correct node returned")
+	assert.Nil(LoadState(n1).Next, "n1.next")
+}
+
 // TestDeleteFromStalePosition verifies deletition of the node from deleted
 // predecessor
 func TestDeleteFromStalePosition(t *testing.T) {
@@ -121,6 +136,32 @@ func TestDeleteFromStalePosition(t *testing.T) {
 	assert.Equal(DELETE, state.Flags, "n3.flags")
 }
 
+// TestWeakDeleteHelpsFreezedPredecessor verifies WeakDelete completes removal
+// stalled on the predecessor found by backlinks
+func TestWeakDeleteHelpsFreezedPredecessor(t *testing.T) {
+	assert := assert.New(t)
+
+	// n15 has been deleted from n1 and then n1 has been freezed to delete n2 by
+	// a thread which stalled
+	n15 := NewIntNode(15)
+	n1, n2, n3 := makelist(10, FREEZE, 20, NONE, 30, NONE)
+	s := LoadState(n15)
+	s.Next, s.Back, s.Flags = n2, n1, DELETE
+
+	left, result, byme := WeakDelete(n15, n2, &State{})
+	assert.False(result, "deleted")
+	assert.False(byme, "deleted by thread")
+	assert.Equal(n1, left, "correct node returned")
+
+	state := LoadState(n1)
+	assert.Equal(n3, state.Next, "n1.next")
+	assert.Equal(NONE, state.Flags, "n1.flags")
+
+	state = LoadState(n2)
+	assert.Equal(n1, state.Back, "n2.back")
+	assert.Equal(DELETE, state.Flags, "n2.flags")
+}
+
 // TestDeleteFromInvalidPosition verifies deletition of the node from deleted
 // predecessor when new node was installed in between
 func TestDeleteFromInvalidPosition(t *testing.T) {
@@ -157,3 +198,72 @@ func TestDeleteFromInvalidPosition(t *testing.T) {
 	assert.Equal(state.Back, n12, "back")
 	assert.Equal(state.Flags, DELETE, "flags")
 }
+
+// freezedChain builds list head -> n[0] -> ... -> n[size-1] -> tail where
+// head and all n nodes are freezed, so every node but head is being deleted
+func freezedChain(size int) (Node, []Node, Node) {
+	head, tail := NewIntNode(-1), NewIntNode(size)
+	nodes := make([]Node, size)
+	for i := range nodes {
+		nodes[i] = NewIntNode(i)
+	}
+
+	prev := Node(head)
+	for _, n := range append(nodes, tail) {
+		LoadState(prev).Next = n
+		LoadState(prev).Flags = FREEZE
+		prev = n
+	}
+	LoadState(tail).Next = NewIntNode(size + 1)
+	return head, nodes, tail
+}
+
+// TestDeleteFreezedChain verifies removal of a long chain of freezed nodes
+// completes with bounded stack
+func TestDeleteFreezedChain(t *testing.T) {
+	assert := assert.New(t)
+
+	// Recursive helping needs stack proportional to the chain length
+	defer debug.SetMaxStack(debug.SetMaxStack(1 << 20))
+	head, nodes, tail := freezedChain(100000)
+
+	assert.NotNil(Next(head), "next")
+	assert.Equal([]int{100001}, values(head), "chain removed")
+	assert.Equal(NONE, LoadState(head).Flags, "head.flags")
+
+	prev := Node(head)
+	for i, n := range append(nodes, tail) {
+		state := LoadState(n)
+		if !assert.Equal(DELETE, state.Flags, "n%d.flags", i) || !assert.Equal(prev, state.Back, "n%d.back", i) {
+			break
+		}
+		prev = n
+	}
+}
+
+// TestDeleteFreezedChainConcurrent verifies that many threads helping to
+// remove the same freezed chains from different positions agree on result
+func TestDeleteFreezedChainConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	for round := 0; round < 20; round++ {
+		head, nodes, _ := freezedChain(1000)
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				Next(nodes[g*len(nodes)/8])
+				Next(head)
+			}(g)
+		}
+		wg.Wait()
+
+		assert.Equal([]int{1001}, values(head), "chain removed")
+		for i, n := range nodes {
+			if !assert.True(LoadState(n).IsRemoved(), "n%d removed", i) {
+				break
+			}
+		}
+	}
+}
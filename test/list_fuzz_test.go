@@ -0,0 +1,169 @@
+package test
+
+import (
+	"testing"
+
+	. "github.com/xphoenix/linkedlist"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Fuzz tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Operation codes decoded from the fuzzer input. Each operation takes two bytes:
+// the code itself and an argument used to pick a position in the list
+const (
+	opInsert = iota
+	opWeakInsert
+	opDelete
+	opWeakDelete
+	opNext
+	opStallFreeze
+	opStallMark
+	opCount
+)
+
+// rawPred walks raw links from head without helping anybody and returns node
+// which points to the given one, no matter what state it is in
+func rawPred(head, node Node) Node {
+	for cur := head; cur != nil; cur = LoadState(cur).Next {
+		if LoadState(cur).Next == node {
+			return cur
+		}
+	}
+	return nil
+}
+
+// FuzzOperations decodes input into a sequence of list operations and compares
+// list with a plain slice model after every step. Stall operations play a role
+// of goroutines which were suspended in the middle of delete, so FREEZE and
+// DELETE states are left in the list for the following operations to deal with
+func FuzzOperations(f *testing.F) {
+	f.Add([]byte{opInsert, 0, opInsert, 0, opInsert, 1, opDelete, 1, opNext, 0})
+	f.Add([]byte{opInsert, 0, opInsert, 0, opStallFreeze, 0, opInsert, 0, opNext, 0})
+	f.Add([]byte{opInsert, 0, opInsert, 0, opInsert, 0, opStallMark, 1, opWeakInsert, 1, opWeakDelete, 0})
+	f.Add([]byte{opInsert, 0, opInsert, 1, opStallFreeze, 1, opStallMark, 0, opDelete, 0, opNext, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		head, model, value := NewIntNode(-1), []*IntNode{}, 0
+
+		// pred returns node which should precede model position i
+		pred := func(i int) Node {
+			if i == 0 {
+				return head
+			}
+			return model[i-1]
+		}
+
+		for len(data) >= 2 {
+			op, arg := int(data[0])%opCount, int(data[1])
+			data = data[2:]
+
+			switch op {
+			case opInsert, opWeakInsert:
+				pos, update := arg%(len(model)+1), NewIntNode(value)
+				value++
+
+				left := pred(pos)
+				if op == opInsert {
+					if _, ok := Insert(left, update); !ok {
+						t.Fatalf("insert after position %d failed", pos)
+					}
+				} else {
+					// Weak insert gives up once it helped to remove right node, that
+					// is only possible if left one is blocked by a concurrent delete
+					state := LoadState(left)
+					if _, ok := WeakInsert(left, state.Next, &State{}, update); !ok {
+						if state.Flags == NONE {
+							t.Fatalf("weak insert after position %d failed on alive node", pos)
+						}
+						continue
+					}
+				}
+				model = append(model[:pos], append([]*IntNode{update}, model[pos:]...)...)
+
+			case opDelete, opWeakDelete:
+				if len(model) == 0 {
+					continue
+				}
+				pos := arg % len(model)
+				node := model[pos]
+
+				if op == opDelete {
+					if _, removed, byme := Delete(head, node); !removed || !byme {
+						t.Fatalf("delete of position %d failed: %v, %v", pos, removed, byme)
+					}
+				} else {
+					left := rawPred(head, node)
+					flags := LoadState(left).Flags
+					_, removed, byme := WeakDelete(left, node, &State{})
+					if removed != byme {
+						t.Fatalf("weak delete of position %d removed by other thread", pos)
+					}
+					if !removed {
+						// Only a predecessor blocked by a concurrent delete could
+						// reject an operation in a sequential run
+						if flags == NONE {
+							t.Fatalf("weak delete of position %d failed on alive predecessor", pos)
+						}
+						continue
+					}
+				}
+				model = append(model[:pos], model[pos+1:]...)
+
+			case opNext:
+				cur := Node(head)
+				for i := 0; i < len(model); i++ {
+					cur = Next(cur)
+					if cur != model[i] {
+						t.Fatalf("position %d: got %v, want %v", i, cur, model[i])
+					}
+				}
+				if next := Next(cur); next != nil {
+					t.Fatalf("list has extra node after model end: %v", next)
+				}
+
+			case opStallFreeze, opStallMark:
+				if len(model) == 0 {
+					continue
+				}
+				pos := arg % len(model)
+				node := model[pos]
+
+				// Freeze is the linearization point of delete, so once it is done
+				// node is not in the list anymore from the model point of view
+				left := rawPred(head, node)
+				if !UpdateState(left, node, NONE, &State{Next: node, Flags: FREEZE}) {
+					continue
+				}
+				model = append(model[:pos], model[pos+1:]...)
+
+				// Go one step further and mark node itself as removed if nobody
+				// else blocks it
+				if op == opStallMark {
+					next := LoadState(node).Next
+					UpdateState(node, next, NONE, &State{Next: next, Back: left, Flags: DELETE})
+				}
+			}
+		}
+
+		// Full scan should help all stalled deletes and end up with exactly the
+		// model content
+		cur, i := Node(head), 0
+		for next := Next(cur); next != nil; next = Next(cur) {
+			if i >= len(model) || next != model[i] {
+				t.Fatalf("position %d: unexpected node %v", i, next)
+			}
+			cur, i = next, i+1
+		}
+		if i != len(model) {
+			t.Fatalf("list is shorter than model: %d < %d", i, len(model))
+		}
+
+		for cur := Node(head); cur != nil; cur = LoadState(cur).Next {
+			if flags := LoadState(cur).Flags; flags != NONE {
+				t.Fatalf("node %v left in %s state", cur, flags)
+			}
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -108,6 +109,31 @@ func TestInsertInDelete(t *testing.T) {
 	assert.Equal(state.Flags, NONE, "n3.flags")
 }
 
+// TestWeakAppend verifies that node is appended only after alive tail
+func TestWeakAppend(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2, n3 := makelist(10, NONE, 20, NONE, 30, NONE)
+
+	assert.False(WeakAppend(n2, &State{}, NewIntNode(25)), "not a tail")
+	assert.Equal(Node(n3), LoadState(n2).Next, "n2.next")
+
+	tail := NewIntNode(40)
+	assert.True(WeakAppend(n3, &State{}, tail), "appended")
+	assert.Equal([]int{20, 30, 40}, values(n1), "list")
+	assert.Nil(LoadState(tail).Next, "tail.next")
+
+	_, _, removed := makelist(10, NONE, 20, FREEZE, 30, DELETE)
+	assert.False(WeakAppend(removed, &State{}, NewIntNode(40)), "removed tail")
+	assert.Nil(LoadState(removed).Next, "removed.next")
+
+	// Append is reported as any other insert
+	rec := newRecorder()
+	head := &ObservedIntNode{NewIntNode(0), rec}
+	node := NewIntNode(1)
+	assert.True(WeakAppend(head, &State{}, node), "appended")
+	assert.Equal(1, rec.inserted[node], "insert reported")
+}
+
 // TestInsertBeforeDelete verifies that Insert before removed node performs phisical
 // deletition of the node
 func TestInsertBeforeDelete(t *testing.T) {
@@ -140,3 +166,93 @@ func TestInsertBeforeDelete(t *testing.T) {
 	assert.Nil(state.Back, "n3.back")
 	assert.Equal(state.Flags, NONE, "n3.flags")
 }
+
+// TestInsertBefore verifies insertion before alive nodes
+func TestInsertBefore(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2, n3 := makelist(10, NONE, 20, NONE, 30, NONE)
+
+	left, inserted := InsertBefore(n1, n3, NewIntNode(25))
+	assert.True(inserted, "inserted before n3")
+	assert.Equal(n2, left, "after n2")
+
+	left, inserted = InsertBefore(n1, n2, NewIntNode(15))
+	assert.True(inserted, "inserted before n2")
+	assert.Equal(n1, left, "after head")
+	assert.Equal([]int{15, 20, 25, 30}, values(n1), "values")
+
+	_, inserted = InsertBefore(n1, NewIntNode(40), NewIntNode(35))
+	assert.False(inserted, "target not found")
+	assert.Equal([]int{15, 20, 25, 30}, values(n1), "values")
+}
+
+// TestInsertBeforeRemoved verifies that node is not inserted before removed
+// or being removed target
+func TestInsertBeforeRemoved(t *testing.T) {
+	assert := assert.New(t)
+
+	// n2 is being removed
+	n1, n2, n3 := makelist(10, FREEZE, 20, NONE, 30, NONE)
+	_, inserted := InsertBefore(n1, n2, NewIntNode(15))
+	assert.False(inserted, "not inserted")
+	assert.True(LoadState(n2).IsRemoved(), "removal completed")
+	assert.Equal([]int{30}, values(n1), "values")
+
+	// n2 is removed but not unlinked yet
+	n1, n2, n3 = makelist(10, FREEZE, 20, DELETE, 30, NONE)
+	LoadState(n2).Back = n1
+	_, inserted = InsertBefore(n1, n2, NewIntNode(15))
+	assert.False(inserted, "not inserted")
+
+	// Predecessor n2 is removed, so node goes after its predecessor
+	n1, n2, n3 = makelist(10, NONE, 20, DELETE, 30, NONE)
+	LoadState(n1).Next = n3
+	LoadState(n2).Back = n1
+	left, inserted := InsertBefore(n2, n3, NewIntNode(25))
+	assert.True(inserted, "inserted")
+	assert.Equal(n1, left, "walked back")
+	assert.Equal([]int{25, 30}, values(n1), "values")
+}
+
+// TestInsertBeforeConcurrent inserts before nodes which are concurrently
+// deleted and verifies inserted nodes are always linked just before target
+func TestInsertBeforeConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	head := NewIntNode(-1)
+
+	targets := make([]*IntNode, 1000)
+	for i := len(targets) - 1; i >= 0; i-- {
+		targets[i] = NewIntNode(i * 10)
+		Insert(head, targets[i])
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i < len(targets); i += 2 {
+			Delete(head, targets[i])
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := len(targets) - 1; i >= 0; i-- {
+			InsertBefore(head, targets[i], NewIntNode(i*10-5))
+		}
+	}()
+	wg.Wait()
+
+	// Every node inserted before alive target is followed by it, targets with
+	// odd index are deleted
+	vals := values(head)
+	for i, v := range vals {
+		if v%10 != 0 && (v+5)/10%2 == 0 {
+			if !assert.True(i+1 < len(vals) && vals[i+1] == v+5, "%d is before its target", v) {
+				break
+			}
+		}
+	}
+	for i := 0; i < len(targets); i += 2 {
+		assert.Contains(vals, i*10-5, "inserted before alive target %d", i)
+	}
+}
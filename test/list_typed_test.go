@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Typed list tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestListZeroValue verifies zero list is ready to use
+func TestListZeroValue(t *testing.T) {
+	assert := assert.New(t)
+
+	var l List[string]
+	assert.Nil(l.Front(), "empty")
+
+	b := l.PushFront("b")
+	l.PushFront("a")
+	l.InsertAfter("c", b)
+	assert.Equal([]string{"a", "b", "c"}, l.Values(), "values")
+
+	assert.True(l.Remove(b), "removed")
+	assert.False(l.Remove(b), "removed twice")
+	assert.Equal([]string{"a", "c"}, l.Values(), "values")
+}
+
+// TestListJSON verifies JSON round trip
+func TestListJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	type config struct {
+		Hosts List[string] `json:"hosts"`
+	}
+
+	var c config
+	assert.NoError(json.Unmarshal([]byte(`{"hosts":["a","b","c"]}`), &c), "decoded")
+	assert.Equal([]string{"a", "b", "c"}, c.Hosts.Values(), "values")
+
+	c.Hosts.Remove(c.Hosts.Front())
+	data, err := json.Marshal(&c)
+	assert.NoError(err, "encoded")
+	assert.Equal(`{"hosts":["b","c"]}`, string(data), "deleted skipped")
+
+	data, err = json.Marshal(NewList[int]())
+	assert.NoError(err, "encoded")
+	assert.Equal(`[]`, string(data), "empty list")
+}
+
+// TestListGob verifies gob round trip
+func TestListGob(t *testing.T) {
+	assert := assert.New(t)
+	l := NewList[int]()
+	for i := 0; i < 10; i++ {
+		l.PushFront(i)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(gob.NewEncoder(&buf).Encode(l), "encoded")
+
+	restored := NewList[int]()
+	assert.NoError(gob.NewDecoder(&buf).Decode(restored), "decoded")
+	assert.Equal(l.Values(), restored.Values(), "values")
+}
+
+// TestListMarshalConcurrent verifies list could be encoded while modified
+func TestListMarshalConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	l := NewList[int]()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			e := l.PushFront(i)
+			if i%2 == 0 {
+				l.Remove(e)
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		data, err := json.Marshal(l)
+		assert.NoError(err, "encoded")
+
+		var values []int
+		assert.NoError(json.Unmarshal(data, &values), "valid json")
+	}
+	wg.Wait()
+	assert.Len(l.Values(), 500, "odd values left")
+}
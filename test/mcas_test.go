@@ -0,0 +1,192 @@
+package test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// moveFirst moves the first node of list from to the front of list to with a
+// single MCAS. Function returns false if list is empty, updated concurrently or
+// has a delete in progress
+func moveFirst(from, to Node) bool {
+	sFrom, sTo := LoadState(from), LoadState(to)
+	if sFrom.Next == nil || sFrom.Flags != NONE || sTo.Flags != NONE {
+		return false
+	}
+
+	node := sFrom.Next
+	sNode := LoadState(node)
+	if sNode.Flags != NONE {
+		return false
+	}
+
+	return MCAS(
+		Update{Node: from, Old: sFrom, New: &State{Next: sNode.Next}},
+		Update{Node: node, Old: sNode, New: &State{Next: sTo.Next}},
+		Update{Node: to, Old: sTo, New: &State{Next: node}},
+	)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// MCAS tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestMCASMove verifies node moves between lists atomically
+func TestMCASMove(t *testing.T) {
+	assert := assert.New(t)
+	a, b := NewIntNode(-1), NewIntNode(-1)
+	Insert(a, NewIntNode(2))
+	Insert(a, NewIntNode(1))
+	Insert(b, NewIntNode(3))
+
+	assert.True(moveFirst(a, b), "moved")
+	assert.Equal([]int{2}, values(a), "source")
+	assert.Equal([]int{1, 3}, values(b), "target")
+}
+
+// TestMCASConflict verifies no slot changes if one of them has unexpected state
+func TestMCASConflict(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2 := NewIntNode(1), NewIntNode(2)
+	s1, s2 := LoadState(n1), LoadState(n2)
+
+	stale := &State{}
+	assert.False(MCAS(
+		Update{Node: n1, Old: s1, New: &State{Next: n2}},
+		Update{Node: n2, Old: stale, New: &State{Next: n1}},
+	), "stale state")
+	assert.Same(s1, LoadState(n1), "first slot")
+	assert.Same(s2, LoadState(n2), "second slot")
+
+	assert.True(MCAS(
+		Update{Node: n1, Old: s1, New: &State{Next: n2}},
+		Update{Node: n2, Old: s2, New: &State{Next: n1}},
+	), "expected states")
+	assert.Equal(Node(n2), LoadState(n1).Next, "first slot")
+	assert.Equal(Node(n1), LoadState(n2).Next, "second slot")
+
+	// Placeholders must never leak to list functions
+	assert.False(UpdateState(n1, n1, NONE, &State{}), "unexpected next")
+	assert.True(UpdateState(n1, n2, NONE, &State{}), "expected next")
+}
+
+// TestMCASNilState verifies node which state has never been set is updated
+// as any other
+func TestMCASNilState(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2 := &Link{}, NewIntNode(2)
+	s2 := LoadState(n2)
+
+	assert.False(MCAS(
+		Update{Node: n1, Old: nil, New: &State{Next: n2}},
+		Update{Node: n2, Old: &State{}, New: &State{}},
+	), "stale state")
+	assert.Nil(LoadState(n1), "first slot")
+	assert.Same(s2, LoadState(n2), "second slot")
+
+	assert.True(MCAS(
+		Update{Node: n1, Old: nil, New: &State{Next: n2}},
+		Update{Node: n2, Old: s2, New: &State{}},
+	), "expected states")
+	assert.Equal(Node(n2), LoadState(n1).Next, "first slot")
+}
+
+// TestMCASCompare verifies slot with the same old and new state is compared
+// but not replaced
+func TestMCASCompare(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2 := NewIntNode(1), NewIntNode(2)
+	s1, s2 := LoadState(n1), LoadState(n2)
+
+	assert.False(MCAS(
+		Update{Node: n1, Old: s1, New: &State{Next: n2}},
+		Update{Node: n2, Old: &State{}, New: nil},
+	), "nil is not compare")
+	stale := &State{}
+	assert.False(MCAS(
+		Update{Node: n1, Old: s1, New: &State{Next: n2}},
+		Update{Node: n2, Old: stale, New: stale},
+	), "stale compared state")
+	assert.Same(s1, LoadState(n1), "first slot")
+	assert.Same(s2, LoadState(n2), "second slot")
+
+	assert.True(MCAS(
+		Update{Node: n1, Old: s1, New: &State{Next: n2}},
+		Update{Node: n2, Old: s2, New: s2},
+	), "expected states")
+	assert.Equal(Node(n2), LoadState(n1).Next, "first slot")
+	assert.Same(s2, LoadState(n2), "compared slot kept")
+}
+
+// TestMCASConcurrent moves nodes between two lists concurrently with inserts
+// and deletes and verifies no node is lost or duplicated
+func TestMCASConcurrent(t *testing.T) {
+	const (
+		workers = 4
+		moves   = 2000
+		inserts = 500
+	)
+
+	assert := assert.New(t)
+	a, b := NewIntNode(-1), NewIntNode(-1)
+	for i := 0; i < 100; i++ {
+		Insert(a, NewIntNode(i))
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < moves; i++ {
+				moveFirst(a, b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < moves; i++ {
+				moveFirst(b, a)
+			}
+		}()
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < inserts; i++ {
+				head := a
+				if i%2 == 1 {
+					head = b
+				}
+
+				// First two nodes of every four are deleted right away, the
+				// other two stay
+				node := NewIntNode(1000 + w*inserts + i)
+				Insert(head, node)
+				// Node might be moved to other list while it is searched
+				for i%4 < 2 && !LoadState(node).IsRemoved() {
+					Delete(a, node)
+					Delete(b, node)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var expected []int
+	for i := 0; i < 100; i++ {
+		expected = append(expected, i)
+	}
+	for w := 0; w < workers; w++ {
+		for i := 0; i < inserts; i++ {
+			if i%4 >= 2 {
+				expected = append(expected, 1000+w*inserts+i)
+			}
+		}
+	}
+
+	actual := append(values(a), values(b)...)
+	sort.Ints(actual)
+	assert.Equal(expected, actual, "nodes")
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+	"github.com/xphoenix/linkedlist/metrics"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newStatsList creates a list of given size which reports into returned stats
+func newStatsList(size int) (Node, *Stats) {
+	stats := NewStats()
+	head := &StatsIntNode{NewIntNode(-1), stats}
+	for i := 0; i < size; i++ {
+		Insert(head, &StatsIntNode{NewIntNode(i), stats})
+	}
+	return head, stats
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Metrics tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestPrometheusCollector verifies exported values and labels
+func TestPrometheusCollector(t *testing.T) {
+	assert := assert.New(t)
+	head, stats := newStatsList(3)
+	Delete(head, Next(head))
+
+	collector := metrics.NewCollector("sessions", stats)
+	assert.Equal(10, testutil.CollectAndCount(collector), "metrics count")
+
+	expected := `
+		# HELP linkedlist_length_estimate Number of nodes inserted minus number of nodes deleted.
+		# TYPE linkedlist_length_estimate gauge
+		linkedlist_length_estimate{list="sessions"} 2
+		# HELP linkedlist_operation_retries Number of retries Insert and Delete calls made.
+		# TYPE linkedlist_operation_retries histogram
+		linkedlist_operation_retries_bucket{list="sessions",le="0"} 4
+		linkedlist_operation_retries_bucket{list="sessions",le="1"} 4
+		linkedlist_operation_retries_bucket{list="sessions",le="3"} 4
+		linkedlist_operation_retries_bucket{list="sessions",le="7"} 4
+		linkedlist_operation_retries_bucket{list="sessions",le="15"} 4
+		linkedlist_operation_retries_bucket{list="sessions",le="31"} 4
+		linkedlist_operation_retries_bucket{list="sessions",le="63"} 4
+		linkedlist_operation_retries_bucket{list="sessions",le="+Inf"} 4
+		linkedlist_operation_retries_sum{list="sessions"} 0
+		linkedlist_operation_retries_count{list="sessions"} 4
+		# HELP linkedlist_operation_helps Number of removals Insert and Delete calls completed on behalf of other goroutines.
+		# TYPE linkedlist_operation_helps histogram
+		linkedlist_operation_helps_bucket{list="sessions",le="0"} 4
+		linkedlist_operation_helps_bucket{list="sessions",le="1"} 4
+		linkedlist_operation_helps_bucket{list="sessions",le="3"} 4
+		linkedlist_operation_helps_bucket{list="sessions",le="7"} 4
+		linkedlist_operation_helps_bucket{list="sessions",le="15"} 4
+		linkedlist_operation_helps_bucket{list="sessions",le="31"} 4
+		linkedlist_operation_helps_bucket{list="sessions",le="63"} 4
+		linkedlist_operation_helps_bucket{list="sessions",le="+Inf"} 4
+		linkedlist_operation_helps_sum{list="sessions"} 0
+		linkedlist_operation_helps_count{list="sessions"} 4
+	`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"linkedlist_length_estimate", "linkedlist_operation_retries", "linkedlist_operation_helps")
+	assert.NoError(err, "exported values")
+}
+
+// TestOTelMeter verifies instruments are reported with list attribute
+func TestOTelMeter(t *testing.T) {
+	assert := assert.New(t)
+	_, stats := newStatsList(5)
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	reg, err := metrics.RegisterMeter(provider.Meter("test"), "sessions", stats)
+	assert.NoError(err, "registered")
+	defer reg.Unregister()
+
+	var rm metricdata.ResourceMetrics
+	assert.NoError(reader.Collect(context.Background(), &rm), "collected")
+
+	found := false
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			// Buckets are cumulative, so every bucket counts all inserts
+			if m.Name == "linkedlist.operation.retries.bucket" {
+				sum := m.Data.(metricdata.Sum[int64])
+				assert.Len(sum.DataPoints, RetryBuckets, "buckets")
+				for _, dp := range sum.DataPoints {
+					assert.Equal(int64(5), dp.Value, "cumulative bucket")
+				}
+			}
+			if m.Name != "linkedlist.inserts" {
+				continue
+			}
+
+			sum := m.Data.(metricdata.Sum[int64])
+			assert.Len(sum.DataPoints, 1, "single list")
+			assert.Equal(int64(5), sum.DataPoints[0].Value, "inserts")
+
+			list, _ := sum.DataPoints[0].Attributes.Value("list")
+			assert.Equal("sessions", list.AsString(), "list attribute")
+			found = true
+		}
+	}
+	assert.True(found, "inserts reported")
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+const (
+	tenantList = iota
+	lruList
+)
+
+// Session is an object which lives in tenant and LRU lists at once
+type Session struct {
+	Links
+	id int
+}
+
+func newSession(id int) *Session {
+	return &Session{Links: NewLinks(2), id: id}
+}
+
+// sessionIds returns ids of all sessions after head
+func sessionIds(head Node) []int {
+	var result []int
+	for cur := Next(head); cur != nil; cur = Next(cur) {
+		result = append(result, cur.(Membership).Node.(*Session).id)
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Multi list tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestMultiMembership verifies object is inserted and deleted independently in
+// every list
+func TestMultiMembership(t *testing.T) {
+	assert := assert.New(t)
+	tenant, lru := NewIntNode(-1), NewIntNode(-1)
+	s1, s2 := newSession(1), newSession(2)
+
+	Insert(tenant, Member(s2, tenantList))
+	Insert(tenant, Member(s1, tenantList))
+	Insert(lru, Member(s1, lruList))
+	Insert(lru, Member(s2, lruList))
+	assert.Equal([]int{1, 2}, sessionIds(tenant), "tenant")
+	assert.Equal([]int{2, 1}, sessionIds(lru), "lru")
+
+	// Membership created again finds the same node
+	_, removed, byme := Delete(lru, Member(s2, lruList))
+	assert.True(removed && byme, "deleted from lru")
+	assert.Equal([]int{1}, sessionIds(lru), "lru")
+	assert.Equal([]int{1, 2}, sessionIds(tenant), "tenant intact")
+	assert.True(LoadState(Member(s2, lruList)).IsRemoved(), "lru state")
+	assert.False(LoadState(Member(s2, tenantList)).IsRemoved(), "tenant state")
+
+	_, removed, _ = Delete(lru, Member(s2, tenantList))
+	assert.False(removed, "other list membership not found")
+}
+
+// TestMultiConcurrent runs concurrent operations over two lists sharing the
+// same objects
+func TestMultiConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	tenant, lru := NewIntNode(-1), NewIntNode(-1)
+
+	sessions := make([]*Session, 4000)
+	for i := range sessions {
+		sessions[i] = newSession(i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(sessions); i += 8 {
+				Insert(tenant, Member(sessions[i], tenantList))
+				Insert(lru, Member(sessions[i], lruList))
+				if i%2 == 0 {
+					_, _, byme := Delete(lru, Member(sessions[i], lruList))
+					assert.True(byme, "deleted from lru")
+				} else {
+					_, _, byme := Delete(tenant, Member(sessions[i], tenantList))
+					assert.True(byme, "deleted from tenant")
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for _, id := range sessionIds(tenant) {
+		assert.Equal(0, id%2, "even in tenant")
+	}
+	for _, id := range sessionIds(lru) {
+		assert.Equal(1, id%2, "odd in lru")
+	}
+	assert.Len(sessionIds(tenant), 2000, "tenant")
+	assert.Len(sessionIds(lru), 2000, "lru")
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// recorder is an Observer which counts events per node
+type recorder struct {
+	mu       sync.Mutex
+	inserted map[Node]int
+	deleted  map[Node]int
+	unlinked map[Node]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{inserted: map[Node]int{}, deleted: map[Node]int{}, unlinked: map[Node]int{}}
+}
+
+func (r *recorder) OnInsert(pred, node Node) {
+	r.mu.Lock()
+	r.inserted[node]++
+	r.mu.Unlock()
+}
+
+func (r *recorder) OnLogicalDelete(node Node) {
+	r.mu.Lock()
+	r.deleted[node]++
+	r.mu.Unlock()
+}
+
+func (r *recorder) OnUnlink(pred, node Node) {
+	r.mu.Lock()
+	r.unlinked[node]++
+	r.mu.Unlock()
+}
+
+// ObservedIntNode is an IntNode which reports into a list specific observer
+type ObservedIntNode struct {
+	*IntNode
+	observer Observer
+}
+
+// Observer implements ObservedNode interface
+func (n *ObservedIntNode) Observer() Observer {
+	return n.observer
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Observer tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestObserverEvents verifies insert and delete report all events
+func TestObserverEvents(t *testing.T) {
+	assert := assert.New(t)
+	r := newRecorder()
+	newNode := func(v int) Node { return &ObservedIntNode{NewIntNode(v), r} }
+
+	head, n1 := newNode(-1), newNode(10)
+	Insert(head, n1)
+	Delete(head, n1)
+
+	assert.Equal(1, r.inserted[n1], "inserted")
+	assert.Equal(1, r.deleted[n1], "deleted")
+	assert.Equal(1, r.unlinked[n1], "unlinked")
+}
+
+// TestObserverHelpedUnlink verifies that node unlinked by many helpers is
+// reported once
+func TestObserverHelpedUnlink(t *testing.T) {
+	assert := assert.New(t)
+	r := newRecorder()
+	prev := SetObserver(r)
+	defer SetObserver(prev)
+
+	n1, n2, _ := makelist(10, FREEZE, 20, NONE, 30, NONE)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Next(n1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(0, r.deleted[n2], "freeze was not done by observed operation")
+	assert.Equal(1, r.unlinked[n2], "unlinked once")
+}
+
+// TestObserverConcurrentDelete verifies that node deleted by many goroutines
+// is reported once
+func TestObserverConcurrentDelete(t *testing.T) {
+	assert := assert.New(t)
+	r := newRecorder()
+	newNode := func(v int) Node { return &ObservedIntNode{NewIntNode(v), r} }
+
+	head, nodes := newNode(-1), make([]Node, 100)
+	for i := range nodes {
+		nodes[i] = newNode(i)
+		Insert(head, nodes[i])
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, n := range nodes {
+				Delete(head, n)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, n := range nodes {
+		assert.Equal(1, r.inserted[n], "inserted once")
+		assert.Equal(1, r.deleted[n], "deleted once")
+		assert.Equal(1, r.unlinked[n], "unlinked once")
+	}
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Pool tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestPoolRecycles verifies that in a steady state pooled operations do not
+// allocate
+func TestPoolRecycles(t *testing.T) {
+	assert := assert.New(t)
+	pool, head := NewPool(), NewIntNode(-1)
+
+	var free []Node
+	reuse := func(n Node) { free = append(free, n) }
+	op := func() {
+		var node Node
+		if len(free) > 0 {
+			node, free = free[len(free)-1], free[:len(free)-1]
+		} else {
+			node = NewIntNode(0)
+		}
+
+		g := pool.Enter()
+		g.Insert(head, node)
+		if _, _, byme := g.Delete(head, node); byme {
+			g.RetireNode(node, reuse)
+		}
+		g.Exit()
+	}
+
+	// Warm up pool, so retired objects are available for reuse
+	for i := 0; i < 1024; i++ {
+		op()
+	}
+	assert.Equal(0.0, testing.AllocsPerRun(1000, op), "allocs")
+	assert.Empty(values(head), "empty list")
+}
+
+// TestPoolGuardProtects verifies that state replaced in the list is not reused
+// while guard which could see it is active
+func TestPoolGuardProtects(t *testing.T) {
+	assert := assert.New(t)
+	pool, head := NewPool(), NewIntNode(-1)
+	Insert(head, NewIntNode(0))
+
+	reader := pool.Enter()
+	seen := LoadState(head)
+	expected := *seen
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10000; i++ {
+			node := NewIntNode(i)
+			g := pool.Enter()
+			g.Insert(head, node)
+			g.Delete(head, node)
+			g.Exit()
+		}
+	}()
+	wg.Wait()
+
+	assert.Equal(expected, *seen, "state is intact")
+	reader.Exit()
+	assert.Equal([]int{0}, values(head), "list content")
+}
+
+// TestPoolConcurrent runs pooled inserts, deletes and walks concurrently and
+// verifies that nodes inserted before are never lost
+func TestPoolConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	pool, head := NewPool(), NewIntNode(-1)
+
+	anchors := make([]*IntNode, 32)
+	for i := len(anchors) - 1; i >= 0; i-- {
+		anchors[i] = NewIntNode(i)
+		Insert(head, anchors[i])
+	}
+
+	nodes := sync.Pool{New: func() interface{} { return NewIntNode(-1) }}
+	reuse := func(n Node) { nodes.Put(n) }
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				g := pool.Enter()
+				switch i % 3 {
+				case 0, 1:
+					node := nodes.Get().(*IntNode)
+					g.Insert(anchors[(w+i)%len(anchors)], node)
+					if _, _, byme := g.Delete(head, node); byme {
+						g.RetireNode(node, reuse)
+					}
+				case 2:
+					for cur := g.Next(head); cur != nil; cur = g.Next(cur) {
+						assert.NotNil(LoadState(cur), "state")
+					}
+				}
+				g.Exit()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	expected := make([]int, len(anchors))
+	for i := range expected {
+		expected[i] = i
+	}
+	assert.Equal(expected, values(head), "anchors")
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// ringValues returns values of ring members in order starting from sentinel
+func ringValues(r *Ring) []int {
+	var result []int
+	for cur := Next(r.Sentinel()); cur != r.Sentinel(); cur = Next(cur) {
+		result = append(result, cur.(*IntNode).value)
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Ring tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestRingOperations verifies insert and delete keep ring closed
+func TestRingOperations(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRing()
+	assert.Nil(r.Rotate(), "empty rotate")
+	assert.Nil(r.Cursor().NextMember(), "empty cursor")
+
+	n1, n2, n3 := NewIntNode(1), NewIntNode(2), NewIntNode(3)
+	r.Insert(n3)
+	r.Insert(n1)
+	r.InsertAfter(n1, n2)
+	assert.Equal([]int{1, 2, 3}, ringValues(r), "values")
+	assert.Equal(r.Sentinel(), LoadState(n3).Next, "last links to sentinel")
+
+	// Delete the last member
+	removed, byme := r.Delete(n3)
+	assert.True(removed && byme, "n3 deleted")
+	assert.Equal(r.Sentinel(), LoadState(n2).Next, "n2 links to sentinel")
+	assert.Equal([]int{1, 2}, ringValues(r), "values")
+
+	removed, _ = r.Delete(n3)
+	assert.False(removed, "not found")
+
+	// Sentinel is not a member
+	removed, byme = r.Delete(r.Sentinel())
+	assert.False(removed || byme, "sentinel kept")
+	assert.False(LoadState(r.Sentinel()).IsFreezed(), "sentinel not freezed")
+	assert.Equal([]int{1, 2}, ringValues(r), "values")
+
+	// Ring wraps around
+	assert.Equal(Node(n1), r.Next(n2), "wrap")
+	assert.Equal(Node(n1), r.Next(n3), "next of removed")
+
+	removed, byme = r.Delete(n1)
+	assert.True(removed && byme, "n1 deleted")
+	removed, byme = r.Delete(n2)
+	assert.True(removed && byme, "n2 deleted")
+	assert.Empty(ringValues(r), "empty")
+	assert.Equal(r.Sentinel(), LoadState(r.Sentinel()).Next, "sentinel links to itself")
+}
+
+// TestRingScans verifies functions which scan the list stop at the sentinel
+func TestRingScans(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRing()
+	assert.Equal(0, Len(r.Sentinel()), "empty len")
+
+	n1, n2, n3 := NewIntNode(1), NewIntNode(2), NewIntNode(3)
+	r.Insert(n3)
+	r.Insert(n2)
+	r.Insert(n1)
+	r.Delete(n2)
+
+	snapshot := Snapshot(r.Sentinel())
+	assert.Equal(2, snapshot.Len(), "snapshot len")
+	assert.Equal(Node(n1), snapshot.At(0), "first")
+	assert.Equal(Node(n3), snapshot.At(1), "second")
+	assert.Equal(2, Len(r.Sentinel()), "len")
+
+	var buf bytes.Buffer
+	assert.NoError(Encode(&buf, r.Sentinel(), intCodec), "encoded")
+	restored := NewIntNode(-1)
+	n, err := Decode(&buf, restored, intCodec)
+	assert.NoError(err, "decoded")
+	assert.Equal(2, n, "count")
+	assert.Equal([]int{1, 3}, values(restored), "values")
+}
+
+// TestRingRoundRobin verifies rotation over members
+func TestRingRoundRobin(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRing()
+	for i := 3; i > 0; i-- {
+		r.Insert(NewIntNode(i))
+	}
+
+	var rotated, cursor []int
+	c := r.Cursor()
+	for i := 0; i < 7; i++ {
+		rotated = append(rotated, r.Rotate().(*IntNode).value)
+		cursor = append(cursor, c.NextMember().(*IntNode).value)
+	}
+	assert.Equal([]int{1, 2, 3, 1, 2, 3, 1}, rotated, "rotate")
+	assert.Equal([]int{1, 2, 3, 1, 2, 3, 1}, cursor, "cursor")
+}
+
+// TestRingConcurrent rotates cursors while members are inserted and deleted
+// and verifies cursors never return removed members
+func TestRingConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRing()
+
+	stable := make([]*IntNode, 8)
+	for i := range stable {
+		stable[i] = NewIntNode(i)
+		r.Insert(stable[i])
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				n := NewIntNode(100 + g*1000 + i)
+				r.InsertAfter(stable[(g+i)%len(stable)], n)
+				removed, byme := r.Delete(n)
+				assert.True(removed && byme, "deleted")
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			c := r.Cursor()
+			for i := 0; i < 2000; i++ {
+				assert.NotNil(c.NextMember(), "cursor")
+				assert.NotNil(r.Rotate(), "rotate")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal([]int{7, 6, 5, 4, 3, 2, 1, 0}, ringValues(r), "stable members")
+}
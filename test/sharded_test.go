@@ -0,0 +1,90 @@
+package test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// shardedValues returns sorted values of all nodes of the list
+func shardedValues(l *ShardedList) []int {
+	var result []int
+	l.Each(func(n Node) bool {
+		result = append(result, n.(*IntNode).value)
+		return true
+	})
+	sort.Ints(result)
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Sharded list tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestShardedList verifies inserts and deletes across shards
+func TestShardedList(t *testing.T) {
+	assert := assert.New(t)
+	l := NewShardedList(3)
+	assert.Equal(4, l.Shards(), "rounded up")
+
+	nodes := make([]*IntNode, 100)
+	for i := range nodes {
+		nodes[i] = NewIntNode(i)
+		if i%2 == 0 {
+			l.Insert(nodes[i])
+		} else {
+			l.InsertHint(uint64(i), nodes[i])
+		}
+	}
+	assert.Len(shardedValues(l), 100, "all nodes")
+
+	for i := 0; i < len(nodes); i += 3 {
+		removed, byme := l.Delete(nodes[i])
+		assert.True(removed && byme, "deleted %d", i)
+	}
+	removed, _ := l.Delete(nodes[0])
+	assert.False(removed, "not found")
+
+	var expected []int
+	for i := range nodes {
+		if i%3 != 0 {
+			expected = append(expected, i)
+		}
+	}
+	assert.Equal(expected, shardedValues(l), "values")
+
+	// Each stops once callback returns false
+	count := 0
+	l.Each(func(Node) bool {
+		count++
+		return false
+	})
+	assert.Equal(1, count, "stopped")
+}
+
+// TestShardedListConcurrent runs concurrent inserts and deletes
+func TestShardedListConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	l := NewShardedList(0)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				n := NewIntNode(g*1000 + i)
+				l.InsertHint(uint64(g), n)
+				if i%2 == 0 {
+					_, byme := l.Delete(n)
+					assert.True(byme, "deleted by me")
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Len(shardedValues(l), 4000, "odd nodes left")
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Size tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestLen verifies Len counts only alive nodes
+func TestLen(t *testing.T) {
+	assert := assert.New(t)
+
+	head, _, _ := makelist(10, NONE, 20, NONE, 30, NONE)
+	assert.Equal(2, Len(head), "full list")
+
+	head, _, _ = makelist(10, NONE, 20, FREEZE, 30, NONE)
+	assert.Equal(1, Len(head), "freezed tail")
+	assert.Equal(0, Len(NewIntNode(0)), "empty list")
+}
+
+// TestSizeConcurrent verifies each removal is counted once even if many
+// goroutines delete the same nodes
+func TestSizeConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	size := NewSize()
+	newNode := func(v int) Node { return &ObservedIntNode{NewIntNode(v), size} }
+
+	head, nodes := newNode(-1), make([]Node, 1000)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(nodes); i += 4 {
+				nodes[i] = newNode(i)
+				Insert(head, nodes[i])
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Equal(int64(len(nodes)), size.ApproxLen(), "all inserted")
+
+	// Every goroutine tries to delete every even node
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < len(nodes); i += 2 {
+				Delete(head, nodes[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(int64(len(nodes)/2), size.ApproxLen(), "approximate len")
+	assert.Equal(int64(len(nodes)/2), size.Len(), "size len")
+	assert.Equal(len(nodes)/2, Len(head), "linearizable len")
+}
+
+// TestSizeLenBounds verifies Len never sees delete of a node before its insert
+// while goroutines insert and delete nodes concurrently
+func TestSizeLenBounds(t *testing.T) {
+	const workers = 4
+
+	assert := assert.New(t)
+	size := NewSize()
+	newNode := func(v int) Node { return &ObservedIntNode{NewIntNode(v), size} }
+
+	head := newNode(-1)
+	var wg sync.WaitGroup
+	for g := 0; g < workers; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				node := newNode(g)
+				Insert(head, node)
+				Delete(head, node)
+			}
+		}(g)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		n := size.Len()
+		assert.True(n >= 0 && n <= workers, "len %d out of bounds", n)
+
+		select {
+		case <-done:
+			assert.Equal(int64(0), size.Len(), "empty")
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Snapshot tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestSnapshotSkipsDeleted verifies snapshot contains only alive nodes
+func TestSnapshotSkipsDeleted(t *testing.T) {
+	assert := assert.New(t)
+
+	// n2 is freezed, so n3 is logically deleted
+	n1, n2, n3 := makelist(10, NONE, 20, FREEZE, 30, NONE)
+	s := Snapshot(n1)
+	assert.Equal(1, s.Len(), "len")
+	assert.Equal(n2, s.At(0), "n2")
+
+	// n2 is removed, but still linked
+	n1, n2, n3 = makelist(10, FREEZE, 20, DELETE, 30, NONE)
+	LoadState(n2).Back = n1
+	s = Snapshot(n1)
+	assert.Equal([]Node{n3}, s.Nodes(), "n3")
+
+	// Snapshot does not help to complete removal
+	assert.Equal(FREEZE, LoadState(n1).Flags, "n1.flags")
+	assert.Equal(n2, LoadState(n1).Next, "n1.next")
+}
+
+// TestSnapshotJSON verifies snapshot serialization
+func TestSnapshotJSON(t *testing.T) {
+	assert := assert.New(t)
+	head, _, _ := makelist(10, NONE, 20, NONE, 30, NONE)
+
+	data, err := json.Marshal(Snapshot(head))
+	assert.NoError(err, "encoded")
+	assert.Equal("[20,30]", string(data), "nodes encoded")
+}
+
+// TestSnapshotConsistent verifies that snapshot never sees intermediate list
+// length while writer keeps it between size and size+1
+func TestSnapshotConsistent(t *testing.T) {
+	assert := assert.New(t)
+	size, head := 64, NewIntNode(-1)
+
+	// Writer inserts new node at the head and then deletes the oldest one, so
+	// list is always a contiguous range of values
+	nodes := make([]Node, 0, 4096)
+	for i := 0; i < size; i++ {
+		nodes = append(nodes, NewIntNode(i))
+		Insert(head, nodes[i])
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := size; i < cap(nodes); i++ {
+			nodes = append(nodes, NewIntNode(i))
+			Insert(head, nodes[i])
+			Delete(head, nodes[i-size])
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+
+		s := Snapshot(head)
+		assert.True(s.Len() == size || s.Len() == size+1, "snapshot size %d", s.Len())
+		for i := 1; i < s.Len(); i++ {
+			assert.Equal(s.At(i-1).(*IntNode).value-1, s.At(i).(*IntNode).value, "contiguous")
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"expvar"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// StatsIntNode is an IntNode which reports into a list specific collector
+type StatsIntNode struct {
+	*IntNode
+	stats *Stats
+}
+
+// Stats implements StatsNode interface
+func (n *StatsIntNode) Stats() *Stats {
+	return n.stats
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Stats tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestStatsPerList verifies that nodes implementing StatsNode report into
+// their own collector
+func TestStatsPerList(t *testing.T) {
+	assert := assert.New(t)
+	stats := NewStats()
+	newNode := func(v int) Node { return &StatsIntNode{NewIntNode(v), stats} }
+
+	head, n1, n2 := newNode(-1), newNode(10), newNode(20)
+	Insert(head, n2)
+	Insert(head, n1)
+	_, removed, byme := Delete(head, n1)
+	assert.True(removed && byme, "deleted")
+
+	snapshot := stats.Snapshot()
+	assert.Equal(uint64(2), snapshot.Inserts, "inserts")
+	assert.Equal(uint64(1), snapshot.Deletes, "deletes")
+	assert.Equal(uint64(0), snapshot.CASFailures, "failures")
+	assert.Equal(uint64(0), snapshot.Helps, "helps")
+	// 2 inserts, freeze, mark and unlink
+	assert.Equal(uint64(5), snapshot.CASAttempts, "attempts")
+}
+
+// TestStatsGlobal verifies global collector counts helps and backlink hops
+func TestStatsGlobal(t *testing.T) {
+	assert := assert.New(t)
+	stats := NewStats()
+	prev := SetStats(stats)
+	defer SetStats(prev)
+
+	// Insert after removed node walks back to the alive predecessor
+	n1, n2, n3 := makelist(10, NONE, 20, DELETE, 30, NONE)
+	LoadState(n1).Next = n3
+	LoadState(n2).Back = n1
+	Insert(n2, NewIntNode(25))
+
+	// Next over freezed node helps to complete removal
+	m1, _, _ := makelist(10, FREEZE, 20, NONE, 30, NONE)
+	Next(m1)
+
+	snapshot := stats.Snapshot()
+	assert.Equal(uint64(1), snapshot.Inserts, "inserts")
+	assert.Equal(uint64(1), snapshot.BacklinkHops, "backlink hops")
+	assert.Equal(uint64(1), snapshot.Helps, "helps")
+	assert.Equal(uint64(1), snapshot.CASFailures, "failures")
+}
+
+// TestStatsHelpHistogram verifies operations report number of removals they
+// have completed on behalf of others
+func TestStatsHelpHistogram(t *testing.T) {
+	assert := assert.New(t)
+	stats := NewStats()
+	prev := SetStats(stats)
+	defer SetStats(prev)
+
+	// Insert after freezed node completes removal of its successor first
+	n1, _, _ := makelist(10, FREEZE, 20, NONE, 30, NONE)
+	Insert(n1, NewIntNode(15))
+
+	// Delete without contention helps nobody
+	n4 := NewIntNode(40)
+	Insert(n1, n4)
+	Delete(n1, n4)
+
+	snapshot := stats.Snapshot()
+	assert.Equal(uint64(2), snapshot.HelpHistogram[0], "no helps")
+	assert.Equal(uint64(1), snapshot.HelpHistogram[1], "one help")
+	assert.Equal(uint64(1), snapshot.OperationHelps, "operation helps")
+	assert.Equal(uint64(1), snapshot.Helps, "helps")
+}
+
+// published counts collectors published by tests
+var published int
+
+// TestStatsPublish verifies collector is visible through expvar
+func TestStatsPublish(t *testing.T) {
+	assert := assert.New(t)
+	stats := NewStats()
+
+	// expvar does not allow to publish same name twice, so run test multiple
+	// times with -count flag requires unique name
+	published++
+	name := fmt.Sprintf("linkedlist_test_stats_%d", published)
+	stats.Publish(name)
+
+	v := expvar.Get(name)
+	assert.NotNil(v, "published")
+	assert.Contains(v.String(), `"Inserts":0`, "snapshot encoded")
+}
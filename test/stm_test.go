@@ -0,0 +1,234 @@
+package test
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// txContains reports whether list contains alive node with the given value
+func txContains(tx *Tx, head Node, value int) bool {
+	for cur := tx.Next(head); cur != nil; cur = tx.Next(cur) {
+		if tx.Flags(cur)&DELETE == 0 && cur.(*IntNode).value == value {
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// STM tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestAtomicallyMove verifies transaction deletes node from one list and inserts
+// it into another at once
+func TestAtomicallyMove(t *testing.T) {
+	assert := assert.New(t)
+	a, b := NewIntNode(-1), NewIntNode(-1)
+	Insert(a, NewIntNode(2))
+	Insert(a, NewIntNode(1))
+	Insert(b, NewIntNode(3))
+
+	err := Atomically(func(tx *Tx) error {
+		node := tx.Next(a)
+		tx.Delete(a, node)
+		tx.Insert(b, node)
+		return nil
+	})
+	assert.NoError(err, "commit")
+	assert.Equal([]int{2}, values(a), "source")
+	assert.Equal([]int{1, 3}, values(b), "target")
+}
+
+// TestAtomicallyAbort verifies error returned by transaction discards changes
+func TestAtomicallyAbort(t *testing.T) {
+	assert := assert.New(t)
+	head, node := NewIntNode(-1), NewIntNode(2)
+	Insert(head, NewIntNode(1))
+
+	errAbort := errors.New("abort")
+	err := Atomically(func(tx *Tx) error {
+		assert.True(tx.Insert(head, node), "insert")
+		assert.Equal(Node(node), tx.Next(head), "own write")
+		return errAbort
+	})
+	assert.Equal(errAbort, err, "error")
+	assert.Equal([]int{1}, values(head), "list")
+}
+
+// TestAtomicallyDelete verifies node deleted by transaction looks like deleted
+// by Delete
+func TestAtomicallyDelete(t *testing.T) {
+	assert := assert.New(t)
+	head, node := NewIntNode(-1), NewIntNode(1)
+	Insert(head, NewIntNode(2))
+	Insert(head, node)
+
+	assert.NoError(Atomically(func(tx *Tx) error {
+		assert.False(tx.Delete(node, head), "not a successor")
+		assert.True(tx.Delete(head, node), "delete")
+		assert.False(tx.Delete(head, node), "deleted already")
+		return nil
+	}), "commit")
+	assert.Equal([]int{2}, values(head), "list")
+	assert.True(LoadState(node).IsRemoved(), "removed")
+	assert.Equal(Node(head), LoadState(node).Back, "backlink")
+
+	// List functions still work with the node
+	_, removed, _ := Delete(head, node)
+	assert.False(removed, "delete again")
+}
+
+// TestAtomicallyDeleteAfterRemoved verifies node could not be deleted through
+// a removed predecessor which still links to it
+func TestAtomicallyDeleteAfterRemoved(t *testing.T) {
+	assert := assert.New(t)
+	n1, n2, n3 := makelist(10, NONE, 20, DELETE, 30, NONE)
+	r := newRecorder()
+	defer SetObserver(SetObserver(r))
+
+	// n2 is already unlinked but still leads to n3
+	assert.True(UpdateState(n1, n2, NONE, &State{Next: n3}), "n2 unlinked")
+	assert.NoError(Atomically(func(tx *Tx) error {
+		assert.False(tx.Delete(n2, n3), "removed prev")
+		return nil
+	}), "commit")
+	assert.False(LoadState(n3).IsRemoved(), "n3 alive")
+	assert.Equal([]int{30}, values(n1), "list")
+	assert.Empty(r.unlinked, "nothing unlinked")
+
+	assert.NoError(Atomically(func(tx *Tx) error {
+		assert.True(tx.Delete(n1, n3), "alive prev")
+		return nil
+	}), "commit")
+	assert.Empty(values(n1), "empty")
+}
+
+// TestAtomicallyReadOnly verifies nodes which transaction has only read keep
+// their states
+func TestAtomicallyReadOnly(t *testing.T) {
+	assert := assert.New(t)
+	head, other, node := NewIntNode(-1), NewIntNode(-1), NewIntNode(2)
+	Insert(other, NewIntNode(1))
+	states := []*State{LoadState(other), LoadState(LoadState(other).Next)}
+
+	assert.NoError(Atomically(func(tx *Tx) error {
+		if !txContains(tx, other, 2) {
+			tx.Insert(head, node)
+		}
+		return nil
+	}), "commit")
+	assert.Equal([]int{2}, values(head), "inserted")
+	assert.Same(states[0], LoadState(other), "head kept")
+	assert.Same(states[1], LoadState(LoadState(other).Next), "node kept")
+}
+
+// TestAtomicallyObserved verifies inserts and deletes of transactions are
+// reported to the list observer in ticket order
+func TestAtomicallyObserved(t *testing.T) {
+	const workers = 4
+
+	assert := assert.New(t)
+	size := NewSize()
+	newNode := func(v int) Node { return &ObservedIntNode{NewIntNode(v), size} }
+
+	head := newNode(-1)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				node := newNode(w)
+				assert.NoError(Atomically(func(tx *Tx) error {
+					tx.Insert(head, node)
+					return nil
+				}), "insert")
+				assert.NoError(Atomically(func(tx *Tx) error {
+					for prev, cur := head, tx.Next(head); cur != nil; prev, cur = cur, tx.Next(cur) {
+						if cur == node {
+							tx.Delete(prev, node)
+						}
+					}
+					return nil
+				}), "delete")
+			}
+		}(w)
+
+		// Plain list operations mixed with transactions
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				node := newNode(w)
+				Insert(head, node)
+				if i%2 == 0 {
+					Delete(head, node)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Equal(int64(workers*100), size.Len(), "len")
+	assert.Equal(workers*100, Len(head), "nodes")
+}
+
+// TestAtomicallyCheckThenInsert inserts values into one of two lists unless any
+// list has it already, concurrently with other inserts and deletes
+func TestAtomicallyCheckThenInsert(t *testing.T) {
+	const (
+		workers = 4
+		count   = 50
+	)
+
+	assert := assert.New(t)
+	a, b := NewIntNode(-1), NewIntNode(-1)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				head := a
+				if (i+w)%2 == 1 {
+					head = b
+				}
+
+				node := NewIntNode(i)
+				err := Atomically(func(tx *Tx) error {
+					if txContains(tx, a, i) || txContains(tx, b, i) {
+						return nil
+					}
+					tx.Insert(head, node)
+					return nil
+				})
+				assert.NoError(err, "commit")
+			}
+		}(w)
+
+		// Noise which makes transactions conflict
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				node := NewIntNode(-100 - w*count - i)
+				Insert(a, node)
+				Delete(a, node)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	actual := append(values(a), values(b)...)
+	sort.Ints(actual)
+
+	expected := make([]int, count)
+	for i := range expected {
+		expected[i] = i
+	}
+	assert.Equal(expected, actual, "every value once")
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/xphoenix/linkedlist"
+)
+
+// fakeClock is a manually advanced Clock
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	ticks chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0), ticks: make(chan time.Time)}
+}
+
+// Now implements Clock interface
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker implements Clock interface
+func (c *fakeClock) NewTicker(d time.Duration) Ticker {
+	return fakeTicker{c.ticks}
+}
+
+// Advance moves clock forward
+func (c *fakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+// fakeTicker delivers ticks sent by test
+type fakeTicker struct {
+	ticks chan time.Time
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.ticks
+}
+
+func (t fakeTicker) Stop() {
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Timer wheel tests
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TestTimerWheelExpiry verifies timers run once their delay passes, including
+// timers kept in upper levels
+func TestTimerWheelExpiry(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	w := NewTimerWheel(time.Millisecond, clock)
+
+	fired := map[int]uint64{}
+	for _, delay := range []int{0, 1, 5, 63, 64, 65, 1000, 4096, 300000} {
+		delay := delay
+		w.Schedule(time.Duration(delay)*time.Millisecond, func() { fired[delay] = w.Now() })
+	}
+
+	w.Advance(clock.Advance(time.Millisecond))
+	assert.Equal(map[int]uint64{0: 1, 1: 1}, fired, "first tick")
+
+	w.Advance(clock.Advance(299999 * time.Millisecond))
+	assert.Equal(map[int]uint64{0: 1, 1: 1, 5: 5, 63: 63, 64: 64, 65: 65, 1000: 1000, 4096: 4096, 300000: 300000}, fired, "all timers")
+}
+
+// TestTimerWheelCancel verifies cancel beats expiry only before timer runs
+func TestTimerWheelCancel(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	w := NewTimerWheel(time.Millisecond, clock)
+
+	var fired int32
+	run := func() { atomic.AddInt32(&fired, 1) }
+
+	short, long := w.Schedule(time.Millisecond, run), w.Schedule(time.Second, run)
+	w.Advance(clock.Advance(100 * time.Millisecond))
+	assert.Equal(int32(1), fired, "short fired")
+	assert.False(w.Cancel(short), "short already fired")
+
+	// Long timer has moved down the wheel, cancel finds its current node
+	assert.True(w.Cancel(long), "long canceled")
+	assert.False(w.Cancel(long), "canceled once")
+	w.Advance(clock.Advance(time.Second))
+	assert.Equal(int32(1), fired, "long not fired")
+}
+
+// TestTimerWheelConcurrent races cancels with expiry and verifies every timer
+// either runs or is canceled, never both
+func TestTimerWheelConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	w := NewTimerWheel(time.Millisecond, clock)
+
+	const count = 4000
+	var fired [count]int32
+	timers := make([]*Timer, count)
+	for i := range timers {
+		i := i
+		timers[i] = w.Schedule(time.Duration(i%200)*time.Millisecond, func() { atomic.AddInt32(&fired[i], 1) })
+	}
+
+	var canceled [count]bool
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 300; i++ {
+			w.Advance(clock.Advance(time.Millisecond))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range timers {
+			canceled[i] = w.Cancel(timers[i])
+		}
+	}()
+	wg.Wait()
+
+	for i := range timers {
+		if canceled[i] {
+			assert.Zero(fired[i], "canceled timer %d fired", i)
+		} else {
+			assert.Equal(int32(1), fired[i], "timer %d fired once", i)
+		}
+	}
+}
+
+// TestTimerWheelRun verifies ticker driven expiry loop
+func TestTimerWheelRun(t *testing.T) {
+	assert := assert.New(t)
+	clock := newFakeClock()
+	w := NewTimerWheel(time.Millisecond, clock)
+
+	done := make(chan struct{})
+	w.Schedule(10*time.Millisecond, func() { close(done) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() { result <- w.Run(ctx) }()
+
+	clock.ticks <- clock.Advance(10 * time.Millisecond)
+	<-done
+	cancel()
+	assert.Equal(context.Canceled, <-result, "stopped")
+}
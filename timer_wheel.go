@@ -0,0 +1,230 @@
+package linkedlist
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Layout of timer wheel: every level has wheelSlots slots and covers
+// wheelSlots times more ticks than the previous one
+const (
+	wheelBits   = 6
+	wheelSlots  = 1 << wheelBits
+	wheelMask   = wheelSlots - 1
+	wheelLevels = 4
+)
+
+// Timer is a handle of a scheduled function
+type Timer struct {
+	fn func()
+
+	// node is a current node of the timer, expiry loop replaces it when timer
+	// moves to a lower level
+	node unsafe.Pointer
+
+	// canceled is set by the Cancel call which stopped timer
+	canceled uint32
+}
+
+// load returns current node of the timer
+func (t *Timer) load() *timerNode {
+	return (*timerNode)(atomic.LoadPointer(&t.node))
+}
+
+// store sets current node of the timer
+func (t *Timer) store(n *timerNode) {
+	atomic.StorePointer(&t.node, unsafe.Pointer(n))
+}
+
+// timerNode is a node of a wheel slot list
+type timerNode struct {
+	state  *State
+	timer  *Timer
+	expiry uint64
+	slot   Node
+}
+
+// State implements Node interface
+func (n *timerNode) State() **State {
+	return &n.state
+}
+
+// TimerWheel is a hierarchical timing wheel. Every slot of the wheel is a
+// lock-free list of timers, so Schedule and Cancel never block each other and
+// the expiry loop.
+//
+// Timer expires in the tick its delay ends, timers of the same tick run in no
+// particular order. Timers scheduled far ahead are kept in upper levels and
+// move down as time goes, the wheel covers 2^24 ticks and timers with longer
+// delays circle in the top level until their time comes.
+//
+// Scheduled functions run on the goroutine which advances the wheel, so they
+// should be short or start goroutines on their own
+type TimerWheel struct {
+	clock Clock
+	tick  time.Duration
+	start time.Time
+
+	// now is the last tick processed or being processed by expiry loop. Only
+	// expiry loop changes it
+	now uint64
+
+	slots [wheelLevels][wheelSlots]Link
+}
+
+// NewTimerWheel creates a wheel with given tick duration using clock as a
+// source of time. Nil clock means SystemClock
+func NewTimerWheel(tick time.Duration, clock Clock) *TimerWheel {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	w := &TimerWheel{clock: clock, tick: tick, start: clock.Now()}
+	for l := range w.slots {
+		for s := range w.slots[l] {
+			w.slots[l][s].state = &State{}
+		}
+	}
+	return w
+}
+
+// Schedule runs fn once delay passes. Returned timer could be used to cancel it
+func (w *TimerWheel) Schedule(delay time.Duration, fn func()) *Timer {
+	at := w.clock.Now().Add(delay).Sub(w.start)
+	if at < 0 {
+		at = 0
+	}
+
+	expiry, t := uint64((at+w.tick-1)/w.tick), &Timer{fn: fn}
+	for {
+		now := atomic.LoadUint64(&w.now)
+		if expiry <= now {
+			expiry = now + 1
+		}
+
+		n := &timerNode{state: &State{}, timer: t, expiry: expiry}
+		due := w.assign(n, now)
+		t.store(n)
+		Insert(n.slot, n)
+
+		// Expiry loop might pass the slot while node has been linked. Take it
+		// back and schedule again, if expiry loop got node first it has run
+		// timer already
+		if atomic.LoadUint64(&w.now) < due {
+			return t
+		}
+		if _, _, byme := Delete(n.slot, n); !byme {
+			return t
+		}
+	}
+}
+
+// Cancel stops timer. Function returns true if timer has been stopped by this
+// call and false if it has run or has been canceled already
+func (w *TimerWheel) Cancel(t *Timer) bool {
+	for {
+		n := t.load()
+		if _, _, byme := Delete(n.slot, n); byme {
+			return atomic.CompareAndSwapUint32(&t.canceled, 0, 1)
+		}
+
+		// Node has been deleted by expiry loop. If timer has not got a new
+		// node then it has run
+		if t.load() == n {
+			return false
+		}
+	}
+}
+
+// assign selects slot for the node at the given tick and returns tick when
+// expiry loop processes the slot
+func (w *TimerWheel) assign(n *timerNode, now uint64) uint64 {
+	expiry := n.expiry
+	if limit := now + 1<<(wheelBits*wheelLevels) - 1; expiry > limit {
+		expiry = limit
+	}
+
+	level := 0
+	for level < wheelLevels-1 && expiry-now >= 1<<(wheelBits*(level+1)) {
+		level++
+	}
+
+	shift := wheelBits * level
+	n.slot = &w.slots[level][(expiry>>shift)&wheelMask]
+	return expiry &^ (1<<shift - 1)
+}
+
+// Now returns number of ticks processed by the wheel
+func (w *TimerWheel) Now() uint64 {
+	return atomic.LoadUint64(&w.now)
+}
+
+// Tick advances wheel by one tick and runs timers expired in it
+func (w *TimerWheel) Tick() {
+	now := atomic.AddUint64(&w.now, 1)
+
+	// Move timers of upper levels down first, so timers which moved to the
+	// current slot run in this tick
+	for level := wheelLevels - 1; level >= 0; level-- {
+		shift := wheelBits * level
+		if now&(1<<shift-1) == 0 {
+			w.process(&w.slots[level][(now>>shift)&wheelMask], now)
+		}
+	}
+}
+
+// process runs expired timers of the slot and moves the others to slots they
+// belong now
+func (w *TimerWheel) process(slot *Link, now uint64) {
+	for cur := Next(slot); cur != nil; cur = Next(cur) {
+		n := cur.(*timerNode)
+		if LoadState(n).IsRemoved() {
+			continue
+		}
+
+		if n.expiry <= now {
+			// Delete decides whether expiry beats Cancel
+			if _, _, byme := Delete(slot, n); byme {
+				n.timer.fn()
+			}
+			continue
+		}
+
+		// Fresh node is linked before the old one is deleted, so Cancel always
+		// finds one of them
+		fresh := &timerNode{state: &State{}, timer: n.timer, expiry: n.expiry}
+		w.assign(fresh, now)
+		Insert(fresh.slot, fresh)
+		n.timer.store(fresh)
+		if _, _, byme := Delete(slot, n); !byme {
+			// Cancel has won, remove fresh node as well
+			Delete(fresh.slot, fresh)
+		}
+	}
+}
+
+// Advance runs ticks until the wheel reaches the given time
+func (w *TimerWheel) Advance(now time.Time) {
+	target := uint64(now.Sub(w.start) / w.tick)
+	for atomic.LoadUint64(&w.now) < target {
+		w.Tick()
+	}
+}
+
+// Run advances the wheel on every tick of the clock until context is done.
+// Only one goroutine could advance the wheel
+func (w *TimerWheel) Run(ctx context.Context) error {
+	ticker := w.clock.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+			w.Advance(w.clock.Now())
+		}
+	}
+}